@@ -0,0 +1,38 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizeWithAccess(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	_, err := memoizer.Memoize("base", func() (int, error) {
+		return 40, nil
+	})
+	require.NoError(t, err)
+
+	result, err := memoizer.MemoizeWithAccess("derived", func(get func(string) (int, bool)) (int, error) {
+		base, ok := get("base")
+		if !ok {
+			return 0, errors.New("base not cached")
+		}
+		_, ok = get("missing")
+		assert.False(t, ok, "get should not compute missing keys")
+		_, ok = get("derived")
+		assert.False(t, ok, "reading the key being computed should not deadlock")
+		return base + 2, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+
+	result, err = memoizer.Memoize("derived", func() (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
@@ -0,0 +1,38 @@
+package memoizer
+
+import "sync"
+
+// maxAdmissionCandidates bounds the number of not-yet-admitted keys whose request
+// counts are tracked, so a stream of one-off keys cannot grow the filter without limit.
+const maxAdmissionCandidates = 4096
+
+// admissionFilter counts misses for keys that have not been cached yet.
+type admissionFilter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// admit records a miss for key and reports whether it has now been requested at
+// least threshold times. Admitted keys stop being tracked.
+func (f *admissionFilter) admit(key string, threshold int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.counts == nil {
+		f.counts = make(map[string]int)
+	}
+	count := f.counts[key] + 1
+	if count >= threshold {
+		delete(f.counts, key)
+		return true
+	}
+	if _, tracked := f.counts[key]; !tracked && len(f.counts) >= maxAdmissionCandidates {
+		// Drop an arbitrary candidate to make room; it simply starts counting again.
+		for candidate := range f.counts {
+			delete(f.counts, candidate)
+			break
+		}
+	}
+	f.counts[key] = count
+	return false
+}
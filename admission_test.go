@@ -0,0 +1,45 @@
+package memoizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithAdmissionThreshold(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	callCount := 0
+	fn := func() (int, error) {
+		callCount++
+		return callCount, nil
+	}
+
+	// The first two misses recompute without caching.
+	for i := 1; i <= 2; i++ {
+		result, err := memoizer.Memoize("key", fn, WithAdmissionThreshold(3))
+		require.NoError(t, err)
+		assert.Equal(t, i, result)
+		_, cached := memoizer.store.Get("key")
+		assert.False(t, cached, "key requested fewer than 3 times should not be cached")
+	}
+
+	// The third miss is admitted and cached.
+	result, err := memoizer.Memoize("key", fn, WithAdmissionThreshold(3))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result)
+
+	result, err = memoizer.Memoize("key", fn, WithAdmissionThreshold(3))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result, "admitted key should be served from cache")
+	assert.Equal(t, 3, callCount)
+}
+
+func TestAdmissionFilterIsBounded(t *testing.T) {
+	var filter admissionFilter
+	for i := 0; i < maxAdmissionCandidates*2; i++ {
+		assert.False(t, filter.admit(fmt.Sprintf("key%d", i), 2))
+	}
+	assert.LessOrEqual(t, len(filter.counts), maxAdmissionCandidates)
+}
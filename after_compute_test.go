@@ -0,0 +1,42 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithAfterCompute(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	hookCalls := 0
+	afterCompute := WithAfterCompute(func(key string, value string, set func(k string, v string, opts ...Option)) {
+		hookCalls++
+		set("by-email:alice@example.com", value)
+	})
+
+	result, err := memoizer.Memoize("user:1", func() (string, error) {
+		return "alice", nil
+	}, afterCompute)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", result)
+
+	// The extra key populated by the hook is now a hit.
+	result, err = memoizer.Memoize("by-email:alice@example.com", func() (string, error) {
+		return "", errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", result)
+
+	// The hook does not run for cache hits or failed computations.
+	_, err = memoizer.Memoize("user:1", func() (string, error) {
+		return "", errors.New("this should not be called")
+	}, afterCompute)
+	require.NoError(t, err)
+	_, err = memoizer.Memoize("user:2", func() (string, error) {
+		return "", errors.New("intentional error")
+	}, afterCompute)
+	require.Error(t, err)
+	assert.Equal(t, 1, hookCalls)
+}
@@ -0,0 +1,42 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithAuditLog(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	var entries []AuditEntry
+	audit := WithAuditLog(func(entry AuditEntry) {
+		entries = append(entries, entry)
+	}, func(result interface{}) string {
+		return fmt.Sprintf("value=%d", result)
+	})
+
+	before := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := memoizer.Memoize("key", func() (int, error) {
+			return 42, nil
+		}, audit)
+		require.NoError(t, err)
+	}
+	require.Len(t, entries, 1, "only the computation should be audited, not cache hits")
+	assert.Equal(t, "key", entries[0].Key)
+	assert.Equal(t, "value=42", entries[0].Summary)
+	assert.False(t, entries[0].Time.Before(before))
+	assert.NoError(t, entries[0].Err)
+
+	_, err := memoizer.Memoize("failing", func() (int, error) {
+		return 0, errors.New("intentional error")
+	}, audit)
+	require.Error(t, err)
+	require.Len(t, entries, 2)
+	assert.EqualError(t, entries[1].Err, "intentional error")
+	assert.Empty(t, entries[1].Summary)
+}
@@ -0,0 +1,88 @@
+package memoizer
+
+import (
+	"sync"
+	"time"
+)
+
+// autoRefresher tracks the keys that are being refreshed on a schedule by WithAutoRefresh.
+type autoRefresher struct {
+	mu      sync.Mutex
+	keys    map[string]bool
+	closed  chan struct{} // closed by close
+	running sync.WaitGroup
+}
+
+// start marks key as refreshed, and reports false if it already was or if the refresher is
+// closed. It returns the channel closed by close.
+func (r *autoRefresher) start(key string) (<-chan struct{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed == nil {
+		r.closed = make(chan struct{})
+	}
+	select {
+	case <-r.closed:
+		return nil, false
+	default:
+	}
+	if r.keys[key] {
+		return nil, false
+	}
+	if r.keys == nil {
+		r.keys = make(map[string]bool)
+	}
+	r.keys[key] = true
+	r.running.Add(1)
+	return r.closed, true
+}
+
+// stop marks key as no longer refreshed.
+func (r *autoRefresher) stop(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.keys, key)
+	r.running.Done()
+}
+
+// close stops every refresh, waiting for those in progress to finish, and prevents new ones.
+func (r *autoRefresher) close() {
+	r.mu.Lock()
+	if r.closed == nil {
+		r.closed = make(chan struct{})
+	}
+	select {
+	case <-r.closed:
+	default:
+		close(r.closed)
+	}
+	r.mu.Unlock()
+	r.running.Wait()
+}
+
+// scheduleRefresh recomputes key with fn every opts.autoRefresh for as long as it is served
+// from the cache between refreshes. It does nothing if key is already being refreshed.
+func (m *Memoizer[T]) scheduleRefresh(key string, fn func() (T, error), opts callOptions[T]) {
+	closed, ok := m.autoRefresher.start(key)
+	if !ok {
+		return
+	}
+	go func() {
+		defer m.autoRefresher.stop(key)
+		ticker := time.NewTicker(opts.autoRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-closed:
+				return
+			}
+			// Hits are counted from the last time the key was stored, so a key that was not
+			// served since the last refresh, or that was deleted, is no longer refreshed.
+			if m.access.get(key).hits == 0 {
+				return
+			}
+			_, _, _ = m.singleFlightGroup.Do(key, m.computation(key, fn, opts))
+		}
+	}()
+}
@@ -0,0 +1,63 @@
+package memoizer
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoRefreshKeepsAccessedEntriesWarm(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		return int(calls.Add(1)), nil
+	}
+	refresh := WithAutoRefresh(20 * time.Millisecond)
+
+	value, err := m.Memoize("key", fn, refresh)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	// Keep the entry hot for a few intervals.
+	deadline := time.Now().Add(110 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		_, err := m.Memoize("key", fn, refresh)
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+	}
+	refreshed := calls.Load()
+	assert.GreaterOrEqual(t, refreshed, int32(3))
+	value, ok := m.Peek("key")
+	require.True(t, ok)
+	assert.EqualValues(t, refreshed, value)
+
+	// Once the entry is no longer accessed, refreshing stops.
+	time.Sleep(80 * time.Millisecond)
+	idle := calls.Load()
+	assert.LessOrEqual(t, idle, refreshed+1)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, idle, calls.Load())
+}
+
+func TestAutoRefreshStopsForDeletedEntries(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	refresh := WithAutoRefresh(10 * time.Millisecond)
+	fn := func() (int, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	_, err := m.Memoize("key", fn, refresh)
+	require.NoError(t, err)
+	_, err = m.Memoize("key", fn, refresh)
+	require.NoError(t, err)
+	m.Delete("key")
+
+	time.Sleep(50 * time.Millisecond)
+	assert.EqualValues(t, 1, calls.Load())
+	_, ok := m.Peek("key")
+	assert.False(t, ok)
+}
@@ -0,0 +1,42 @@
+package memoizer
+
+import "context"
+
+// memoizeWithBackend implements a call with WithBackend: it reads and writes opts.backend instead
+// of the Memoizer's store, and shares only the singleflight group with other calls. A panic in
+// fn is returned as a *recoveredPanic error, for the caller to re-panic or convert.
+func (m *Memoizer[T]) memoizeWithBackend(ctx context.Context, key string, fn func() (T, error), opts callOptions[T]) (value T, source Source, shared bool, err error) {
+	if !opts.forceRefresh {
+		if raw, ok := opts.backend.Get(key); ok {
+			if value, ok := m.typed(raw); ok {
+				return value, SourceCache, false, nil
+			}
+		}
+	}
+
+	if m.reentrancy.reentrant(key) || computingFrom(ctx).includes(m, key) {
+		return value, SourceNone, false, ErrReentrantComputation
+	}
+
+	source = SourceShared
+	result, err, shared := m.singleFlightGroup.Do(key, func() (interface{}, error) {
+		source = SourceComputed
+		gid := goroutineID()
+		if err := m.reentrancy.enter(key, gid); err != nil {
+			return nil, err
+		}
+		defer m.reentrancy.exit(key, gid)
+
+		res, err, p := callRecovering(fn)
+		if p != nil {
+			return nil, p
+		}
+		if err != nil {
+			return nil, err
+		}
+		opts.backend.Set(key, res, m.expiration(res, opts))
+		return res, nil
+	})
+	value, err = m.result(result, err)
+	return value, source, shared, err
+}
@@ -0,0 +1,51 @@
+package memoizer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithBackend(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	requestScoped := newMapStore()
+
+	result, err := memoizer.Memoize("key", func() (int, error) { return 1, nil }, WithBackend(requestScoped), WithTTL(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	assert.Equal(t, 1, requestScoped.values["key"], "the result should be stored in the backend of the call")
+	assert.Equal(t, time.Minute, requestScoped.ttls["key"])
+	_, ok := memoizer.Peek("key")
+	assert.False(t, ok, "the result should not be stored in the Memoizer's store")
+
+	result, err = memoizer.MemoizeContext(context.Background(), "key", func(context.Context) (int, error) {
+		t.Fatal("the backend of the call should serve the cached value")
+		return 0, nil
+	}, WithBackend(requestScoped))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	// Other calls use the Memoizer's store.
+	result, err = memoizer.Memoize("key", func() (int, error) { return 2, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 2, result)
+	assert.Equal(t, 1, requestScoped.values["key"])
+}
+
+func TestMemoizerWithBackendPanics(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	panicking := func() (int, error) { panic("boom") }
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = memoizer.Memoize("key", panicking, WithBackend(newMapStore()))
+	})
+
+	r := <-memoizer.MemoizeDoChan("key", panicking, WithBackend(newMapStore()))
+	var panicErr *PanicError
+	require.ErrorAs(t, r.Err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+}
@@ -0,0 +1,61 @@
+package memoizer
+
+import (
+	"fmt"
+	"testing"
+)
+
+type stringerValue struct {
+	name string
+}
+
+func (s stringerValue) String() string {
+	return s.name
+}
+
+func BenchmarkMemoizeHit(b *testing.B) {
+	b.Run("concrete", func(b *testing.B) {
+		memoizer := NewMemoizer[int]()
+		fn := func() (int, error) {
+			return 42, nil
+		}
+		_, _ = memoizer.Memoize("key", fn)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = memoizer.Memoize("key", fn)
+		}
+	})
+
+	b.Run("interface", func(b *testing.B) {
+		memoizer := NewMemoizer[fmt.Stringer]()
+		fn := func() (fmt.Stringer, error) {
+			return stringerValue{name: "value"}, nil
+		}
+		_, _ = memoizer.Memoize("key", fn)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = memoizer.Memoize("key", fn)
+		}
+	})
+}
+
+var (
+	intSink      int
+	stringerSink fmt.Stringer
+)
+
+func BenchmarkTypeAssertion(b *testing.B) {
+	b.Run("concrete", func(b *testing.B) {
+		values := []interface{}{42}
+		for i := 0; i < b.N; i++ {
+			intSink = values[i&0].(int)
+		}
+	})
+
+	b.Run("interface", func(b *testing.B) {
+		values := []interface{}{stringerValue{name: "value"}}
+		for i := 0; i < b.N; i++ {
+			stringerSink = values[i&0].(fmt.Stringer)
+		}
+	})
+}
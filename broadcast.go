@@ -0,0 +1,88 @@
+package memoizer
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Invalidation announces that entries were removed from a Memoizer, so that the Memoizers of
+// other processes can drop their local copies.
+type Invalidation struct {
+	// Origin identifies the Memoizer that published the invalidation.
+	Origin string
+	// Key is the key that was deleted, unless Flush is set.
+	Key string `json:",omitempty"`
+	// Flush reports that every entry was removed.
+	Flush bool `json:",omitempty"`
+}
+
+// Broadcaster carries invalidations between the Memoizers of several processes, as installed
+// with WithBroadcaster. Implementations, such as memoredis.Broadcaster, must be safe for
+// concurrent use.
+type Broadcaster interface {
+	// Publish sends inv to every subscriber, which may include the publishing process.
+	Publish(inv Invalidation) error
+	// Subscribe registers fn to be called with every invalidation received.
+	Subscribe(fn func(inv Invalidation))
+}
+
+// newInstanceID returns a random identifier for the Origin of a Memoizer's invalidations.
+func newInstanceID() string {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// publish broadcasts inv, if a Broadcaster is installed. Failures are logged, since the local
+// invalidation has already taken effect.
+func (m *Memoizer[T]) publish(inv Invalidation) {
+	if m.broadcaster == nil {
+		return
+	}
+	inv.Origin = m.instanceID
+	if err := m.broadcaster.Publish(inv); err != nil && m.logger != nil {
+		m.logger.Warn("memoizer failed to publish invalidation", "key", inv.Key, "flush", inv.Flush, "error", err)
+	}
+}
+
+// publishDeleted publishes the deletion of each of keys.
+func (m *Memoizer[T]) publishDeleted(keys []string) {
+	for _, key := range keys {
+		m.publish(Invalidation{Key: key})
+	}
+}
+
+// receive applies an invalidation published by another Memoizer to the local tier of the
+// store: the local tier of a TieredStore, or else the whole store.
+func (m *Memoizer[T]) receive(inv Invalidation) {
+	if inv.Origin == m.instanceID {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generation.Add(1)
+
+	tiered, isTiered := m.store.(*TieredStore)
+	if inv.Flush {
+		if isTiered {
+			tiered.Local().Flush()
+		} else {
+			m.store.Flush()
+		}
+		m.access.reset()
+		m.errors.reset()
+		m.tags.reset()
+		m.multiKeys = nil
+		return
+	}
+
+	if isTiered {
+		tiered.Local().Delete(inv.Key)
+	} else {
+		m.storeDelete(inv.Key)
+	}
+	m.access.remove(inv.Key)
+	m.errors.remove(inv.Key)
+	m.tags.remove(inv.Key)
+	m.singleFlightGroup.Forget(inv.Key)
+}
@@ -0,0 +1,132 @@
+package memoizer
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// localBus is a Broadcaster that delivers invalidations synchronously within the process.
+type localBus struct {
+	mu          sync.Mutex
+	subscribers []func(Invalidation)
+	published   []Invalidation
+}
+
+func (b *localBus) Publish(inv Invalidation) error {
+	b.mu.Lock()
+	b.published = append(b.published, inv)
+	subscribers := append([]func(Invalidation){}, b.subscribers...)
+	b.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(inv)
+	}
+	return nil
+}
+
+func (b *localBus) Subscribe(fn func(Invalidation)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, fn)
+}
+
+func TestMemoizerWithBroadcaster(t *testing.T) {
+	bus := &localBus{}
+	remote := newMapStore()
+	newInstance := func() (*Memoizer[int], *TieredStore) {
+		store := NewTieredStore(NewGoCacheStore(0), remote, time.Minute)
+		return NewMemoizerWithOptions[int](WithStore(store), WithBroadcaster(bus)), store
+	}
+	first, _ := newInstance()
+	second, secondStore := newInstance()
+
+	first.Set("a", 1, NoExpiration)
+	first.Set("b", 2, NoExpiration)
+	first.Set("c", 3, NoExpiration)
+	for _, key := range []string{"a", "b", "c"} {
+		_, ok := second.Peek(key)
+		require.True(t, ok)
+		_, ok = secondStore.Local().Get(key)
+		require.True(t, ok, "the second instance should have a local copy of %s", key)
+	}
+
+	first.Delete("a")
+	_, ok := secondStore.Local().Get("a")
+	assert.False(t, ok, "the local copy should be evicted")
+	_, ok = secondStore.Local().Get("b")
+	assert.True(t, ok)
+
+	// Renaming evicts the local copies of both keys.
+	require.True(t, first.Rename("b", "c"))
+	_, ok = secondStore.Local().Get("b")
+	assert.False(t, ok)
+	value, ok := second.Peek("c")
+	require.True(t, ok)
+	assert.Equal(t, 2, value, "the renamed value should replace the local copy")
+
+	first.Flush()
+	_, ok = secondStore.Local().Get("c")
+	assert.False(t, ok)
+
+	require.Len(t, bus.published, 4)
+	assert.Equal(t, "a", bus.published[0].Key)
+	assert.Equal(t, "b", bus.published[1].Key)
+	assert.Equal(t, "c", bus.published[2].Key)
+	assert.True(t, bus.published[3].Flush)
+	assert.Equal(t, bus.published[0].Origin, bus.published[3].Origin)
+	assert.True(t, second.Config().Broadcasting)
+}
+
+// rendezvousBus is a localBus whose first two publications wait for each other before being
+// delivered, so that two Memoizers publish at the same time.
+type rendezvousBus struct {
+	localBus
+	arrivals atomic.Int32
+	barrier  sync.WaitGroup
+}
+
+func (b *rendezvousBus) Publish(inv Invalidation) error {
+	if b.arrivals.Add(1) <= 2 {
+		b.barrier.Done()
+		b.barrier.Wait()
+	}
+	return b.localBus.Publish(inv)
+}
+
+func TestMemoizerWithBroadcasterConcurrentInvalidation(t *testing.T) {
+	bus := &rendezvousBus{}
+	bus.barrier.Add(2)
+	first := NewMemoizerWithOptions[int](WithBroadcaster(bus))
+	second := NewMemoizerWithOptions[int](WithBroadcaster(bus))
+	second.Set("a:1", 1, NoExpiration)
+
+	// Each instance publishes while the other receives; publishing under the lock would
+	// deadlock the two with a synchronous Broadcaster.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			first.Flush()
+		}()
+		go func() {
+			defer wg.Done()
+			second.DeletePrefix("a:")
+		}()
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("concurrent invalidations deadlocked")
+	}
+	_, ok := second.Peek("a:1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,157 @@
+package memoizer
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned on a cache miss while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// DefaultCircuitMinRequests is the number of computations a window of the circuit breaker of
+// WithGlobalCircuit must count before its error rate can trip it, unless configured otherwise.
+const DefaultCircuitMinRequests = 5
+
+// globalCircuit trips when the error rate across all computations of a Memoizer
+// exceeds a threshold within a fixed window, and resets after a cooldown.
+type globalCircuit struct {
+	errorRate   float64
+	window      time.Duration
+	cooldown    time.Duration
+	minRequests int // computations a window needs before its error rate is evaluated
+
+	mu          sync.Mutex
+	windowStart time.Time
+	total       int
+	failures    int
+	openUntil   time.Time
+}
+
+// allow reports whether a computation may run at the given time.
+func (c *globalCircuit) allow(now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !now.Before(c.openUntil)
+}
+
+// record registers the outcome of a computation and trips the circuit if the error
+// rate of the current window exceeds the threshold. The rate is only evaluated once the
+// window counts minRequests computations, so that a few isolated failures, such as the first
+// one after a quiet period, do not trip the circuit.
+func (c *globalCircuit) record(now time.Time, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.windowStart) >= c.window {
+		c.windowStart = now
+		c.total = 0
+		c.failures = 0
+	}
+	c.total++
+	if failed {
+		c.failures++
+	}
+	if c.total >= c.minRequests && float64(c.failures)/float64(c.total) > c.errorRate {
+		c.openUntil = now.Add(c.cooldown)
+		// Start counting afresh once the cooldown has elapsed.
+		c.windowStart = c.openUntil
+		c.total = 0
+		c.failures = 0
+	}
+}
+
+// keyCircuits holds a circuit breaker per key, each of which trips after a number of
+// consecutive failed computations of its key and resets after a cooldown. The state of a key
+// is dropped once a cooldown has passed since its last failure, so that keys which fail once
+// and are never computed again do not accumulate.
+type keyCircuits struct {
+	failures int
+	cooldown time.Duration
+
+	mu    sync.Mutex
+	state map[string]keyCircuit
+	// sweepAt is the number of keys with state at which stale state is next dropped.
+	sweepAt int
+}
+
+// minKeyCircuitSweep is the number of keys with state below which keyCircuits does not bother
+// dropping stale state.
+const minKeyCircuitSweep = 64
+
+// keyCircuit is the state of the circuit breaker of one key.
+type keyCircuit struct {
+	failures  int
+	openUntil time.Time
+	// staleAt is when the state no longer matters: the circuit is closed and the failures
+	// are too old to count.
+	staleAt time.Time
+}
+
+// allow reports whether a computation of key may run at the given time.
+func (c *keyCircuits) allow(key string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !now.Before(c.state[key].openUntil)
+}
+
+// record registers the outcome of a computation of key and trips its circuit once it failed
+// too many times in a row.
+func (c *keyCircuits) record(key string, now time.Time, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !failed {
+		delete(c.state, key)
+		return
+	}
+	if c.state == nil {
+		c.state = make(map[string]keyCircuit)
+	}
+	state, ok := c.state[key]
+	if !ok && len(c.state) >= c.sweepAt {
+		c.sweep(now)
+	}
+	if !now.Before(state.staleAt) {
+		state = keyCircuit{}
+	}
+	state.failures++
+	state.staleAt = now.Add(c.cooldown)
+	if state.failures >= c.failures {
+		state = keyCircuit{openUntil: state.staleAt, staleAt: state.staleAt}
+	}
+	c.state[key] = state
+}
+
+// sweep drops the stale state, and schedules the next sweep for when the number of keys with
+// state has doubled. The caller must hold c.mu.
+func (c *keyCircuits) sweep(now time.Time) {
+	for key, state := range c.state {
+		if !now.Before(state.staleAt) {
+			delete(c.state, key)
+		}
+	}
+	c.sweepAt = 2 * len(c.state)
+	if c.sweepAt < minKeyCircuitSweep {
+		c.sweepAt = minKeyCircuitSweep
+	}
+}
+
+// circuitOpen reports whether a circuit breaker rejects computing key. A rejected call is
+// served the expired value retained by WithServeStaleOnError, if any, as it would be if the
+// computation had failed; otherwise it fails with ErrCircuitOpen.
+func (m *Memoizer[T]) circuitOpen(key string, opts callOptions[T]) (value T, err error, open bool) {
+	now := time.Now()
+	if (m.circuit == nil || m.circuit.allow(now)) && (m.keyCircuits == nil || m.keyCircuits.allow(key, now)) {
+		return value, nil, false
+	}
+	if opts.staleOnErrorFor > 0 {
+		if stale, ok := m.retained(key); ok {
+			if opts.reportStaleOnError {
+				return stale, &StaleError{Err: ErrCircuitOpen}, true
+			}
+			return stale, nil, true
+		}
+	}
+	return value, ErrCircuitOpen, true
+}
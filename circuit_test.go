@@ -0,0 +1,154 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithGlobalCircuit(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithGlobalCircuit(0.5, time.Minute, 100*time.Millisecond))
+
+	_, err := memoizer.Memoize("cached", func() (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+
+	// A burst of errors across different keys trips the circuit once the window counts
+	// DefaultCircuitMinRequests computations.
+	for i := 0; i < DefaultCircuitMinRequests-1; i++ {
+		_, err = memoizer.Memoize(fmt.Sprintf("failing%d", i), func() (int, error) {
+			return 0, errors.New("backend down")
+		})
+		require.Error(t, err)
+	}
+
+	callCount := 0
+	_, err = memoizer.Memoize("other", func() (int, error) {
+		callCount++
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 0, callCount, "misses should fail fast while the circuit is open")
+
+	result, err := memoizer.Memoize("cached", func() (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, err, "cache hits should be unaffected by the circuit")
+	assert.Equal(t, 42, result)
+
+	// The circuit recovers after the cooldown.
+	time.Sleep(150 * time.Millisecond)
+	result, err = memoizer.Memoize("other", func() (int, error) {
+		callCount++
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+	assert.Equal(t, 1, callCount)
+}
+
+func TestMemoizerWithGlobalCircuitIgnoresIsolatedErrors(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithGlobalCircuit(0.5, time.Minute, time.Minute))
+
+	// The first computation of a window fails: a 100% error rate, over too few requests.
+	_, err := memoizer.Memoize("failing", func() (int, error) {
+		return 0, errors.New("transient")
+	})
+	require.Error(t, err)
+
+	result, err := memoizer.Memoize("other", func() (int, error) { return 1, nil })
+	require.NoError(t, err, "a single isolated error should not open the circuit")
+	assert.Equal(t, 1, result)
+
+	// The minimum is configurable.
+	memoizer = NewMemoizerWithOptions[int](&GlobalCircuitOption{ErrorRate: 0.5, Window: time.Minute, Cooldown: time.Minute, MinRequests: 1})
+	_, err = memoizer.Memoize("failing", func() (int, error) {
+		return 0, errors.New("transient")
+	})
+	require.Error(t, err)
+	_, err = memoizer.Memoize("other", func() (int, error) { return 1, nil })
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestMemoizerWithCircuitBreaker(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithCircuitBreaker(2, 50*time.Millisecond))
+	failure := errors.New("backend down")
+
+	callCount := 0
+	failing := func() (int, error) {
+		callCount++
+		return 0, failure
+	}
+	for i := 0; i < 2; i++ {
+		_, err := memoizer.Memoize("failing", failing)
+		require.ErrorIs(t, err, failure)
+	}
+
+	_, err := memoizer.Memoize("failing", failing)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, callCount, "misses of the key should fail fast while its circuit is open")
+
+	result, err := memoizer.Memoize("healthy", func() (int, error) { return 1, nil })
+	require.NoError(t, err, "other keys should be unaffected")
+	assert.Equal(t, 1, result)
+
+	time.Sleep(60 * time.Millisecond)
+	result, err = memoizer.Memoize("failing", func() (int, error) { return 2, nil })
+	require.NoError(t, err, "the computation should be attempted after the cooldown")
+	assert.Equal(t, 2, result)
+
+	config := memoizer.Config()
+	assert.Equal(t, 2, config.CircuitBreakerFailures)
+	assert.Equal(t, 50*time.Millisecond, config.CircuitBreakerCooldown)
+}
+
+func TestKeyCircuitsDropStaleState(t *testing.T) {
+	circuits := &keyCircuits{failures: 2, cooldown: time.Minute}
+	now := time.Now()
+
+	// Keys that fail once, or trip their circuit, and are never computed again.
+	for i := 0; i < minKeyCircuitSweep; i++ {
+		circuits.record(fmt.Sprint("once", i), now, true)
+		circuits.record(fmt.Sprint("tripped", i), now, true)
+		circuits.record(fmt.Sprint("tripped", i), now, true)
+	}
+	require.Len(t, circuits.state, 2*minKeyCircuitSweep)
+	assert.False(t, circuits.allow("tripped0", now))
+
+	later := now.Add(time.Minute)
+	circuits.record("other", later, true)
+	assert.Len(t, circuits.state, 1, "the state of keys past their cooldown should be dropped")
+	assert.True(t, circuits.allow("tripped0", later))
+
+	// An old failure no longer counts towards tripping the circuit.
+	circuits.record("other", later.Add(time.Minute), true)
+	assert.True(t, circuits.allow("other", later.Add(time.Minute)))
+}
+
+func TestMemoizerWithCircuitBreakerServesStale(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithCircuitBreaker(1, time.Minute))
+	expiry := WithExpiration(func(interface{}) time.Duration { return 10 * time.Millisecond })
+	fallback := &ServeStaleOnErrorOption{RetainFor: time.Hour, Report: true}
+
+	_, err := memoizer.Memoize("key", func() (int, error) { return 42, nil }, expiry, fallback)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	failure := errors.New("backend down")
+	_, err = memoizer.Memoize("key", func() (int, error) { return 0, failure }, expiry, fallback)
+	require.ErrorIs(t, err, failure)
+
+	result, err := memoizer.Memoize("key", func() (int, error) {
+		t.Fatal("computed while the circuit is open")
+		return 0, nil
+	}, expiry, fallback)
+	var stale *StaleError
+	require.ErrorAs(t, err, &stale)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 42, result)
+}
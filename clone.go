@@ -0,0 +1,76 @@
+package memoizer
+
+import "reflect"
+
+// clone returns the copy of a cached value to hand out to a caller, made with the function
+// installed by WithClone, or value itself without one.
+func (m *Memoizer[T]) clone(value T) T {
+	if m.cloneValue == nil {
+		return value
+	}
+	return m.cloneValue(value)
+}
+
+// DeepCopy returns a deep copy of v, made by reflection, for use with WithClone. It copies
+// pointers, slices, maps, arrays, interfaces and the exported fields of structs recursively;
+// unexported fields, channels and functions are copied shallowly. v must not contain cycles.
+func DeepCopy[T any](v T) T {
+	value := reflect.ValueOf(&v).Elem()
+	copied, _ := deepCopy(value).Interface().(T)
+	return copied
+}
+
+// deepCopy returns a deep copy of v, as described by DeepCopy.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(deepCopy(v.Elem()))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(deepCopy(v.Elem()))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			copied.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(deepCopy(v.Field(i)))
+			}
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,86 @@
+package memoizer
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneProtectsCachedValues(t *testing.T) {
+	m := NewMemoizerWithOptions[[]string](WithClone(slices.Clone[[]string]))
+	fn := func() ([]string, error) {
+		return []string{"a", "b"}, nil
+	}
+
+	computed, err := m.Memoize("key", fn)
+	require.NoError(t, err)
+	computed[0] = "mutated"
+
+	cached, err := m.Memoize("key", fn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, cached)
+	cached[1] = "mutated"
+
+	peeked, ok := m.Peek("key")
+	require.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, peeked)
+}
+
+type document struct {
+	Title    string
+	Tags     []string
+	Meta     map[string][]int
+	Parent   *document
+	Any      interface{}
+	Created  time.Time
+	internal []int
+}
+
+func TestDeepCopy(t *testing.T) {
+	original := document{
+		Title:    "doc",
+		Tags:     []string{"a"},
+		Meta:     map[string][]int{"n": {1}},
+		Parent:   &document{Title: "parent"},
+		Any:      []int{1},
+		Created:  time.Now(),
+		internal: []int{1},
+	}
+
+	copied := DeepCopy(original)
+	assert.Equal(t, original, copied)
+
+	copied.Tags[0] = "b"
+	copied.Meta["n"][0] = 2
+	copied.Parent.Title = "changed"
+	copied.Any.([]int)[0] = 2
+	assert.Equal(t, "a", original.Tags[0])
+	assert.Equal(t, 1, original.Meta["n"][0])
+	assert.Equal(t, "parent", original.Parent.Title)
+	assert.Equal(t, 1, original.Any.([]int)[0])
+
+	// Unexported fields are copied shallowly.
+	copied.internal[0] = 2
+	assert.Equal(t, 2, original.internal[0])
+
+	var nilMap map[string]int
+	assert.Nil(t, DeepCopy(nilMap))
+	var nilInterface interface{}
+	assert.Nil(t, DeepCopy(nilInterface))
+}
+
+func TestCloneWithDeepCopy(t *testing.T) {
+	m := NewMemoizerWithOptions[map[string][]int](WithClone(DeepCopy[map[string][]int]))
+	values, err := m.MemoizeMulti("primary", func() (map[string]map[string][]int, error) {
+		return map[string]map[string][]int{"key": {"n": {1}}}, nil
+	})
+	require.NoError(t, err)
+	values["key"]["n"][0] = 2
+
+	cached, ok := m.Peek("key")
+	require.True(t, ok)
+	assert.Equal(t, []int{1}, cached["n"])
+}
@@ -0,0 +1,128 @@
+package memoizer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+	path := filepath.Join(t.TempDir(), "cache.json")
+	memoizer := New[int](WithCleanupInterval(time.Millisecond), WithPersistence(path, time.Hour, nil))
+
+	var calls atomic.Int32
+	refresh := WithAutoRefresh(time.Millisecond)
+	_, err := memoizer.Memoize("key", func() (int, error) { return int(calls.Add(1)), nil }, refresh)
+	require.NoError(t, err)
+	assert.Greater(t, runtime.NumGoroutine(), before)
+
+	memoizer.Set("persisted", 42, NoExpiration)
+	require.NoError(t, memoizer.Close())
+	require.NoError(t, memoizer.Close())
+	// Not assert.Eventually, which runs the condition in a goroutine of its own.
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	// The cache was written on Close, and the Memoizer remains usable without refreshing.
+	restarted := New[int](WithPersistence(path, 0, nil))
+	value, ok := restarted.Peek("persisted")
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+
+	_, err = memoizer.Memoize("other", func() (int, error) { return 1, nil }, refresh)
+	require.NoError(t, err)
+	refreshed := calls.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, refreshed, calls.Load())
+}
+
+func TestMemoizerCloseLeavesPassedStoresOpen(t *testing.T) {
+	store := NewGoCacheStore(time.Millisecond)
+	t.Cleanup(store.Close)
+	memoizer := New[int](WithStore(store))
+	require.NoError(t, memoizer.Close())
+
+	store.Set("key", 1, time.Millisecond)
+	assert.Eventually(t, func() bool { return store.ItemCount() == 0 }, time.Second, time.Millisecond, "the store still purges expired entries")
+}
+
+func TestMemoizerShutdownAndPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	memoizer := New[int](WithPersistence(path, 0, nil))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	result := memoizer.MemoizeDoChan("slow", func() (int, error) {
+		close(started)
+		<-release
+		return 42, nil
+	})
+	<-started
+
+	done := make(chan error, 1)
+	go func() { done <- memoizer.ShutdownAndPersist(context.Background()) }()
+	assert.Eventually(t, func() bool {
+		_, err := memoizer.Memoize("new", func() (int, error) { return 1, nil })
+		return errors.Is(err, ErrClosed)
+	}, time.Second, time.Millisecond)
+	r := <-memoizer.MemoizeDoChan("new", func() (int, error) { return 1, nil })
+	assert.ErrorIs(t, r.Err, ErrClosed)
+
+	// Shutdown waits for the computation in flight, and persists its result.
+	select {
+	case <-done:
+		t.Fatal("ShutdownAndPersist returned with a computation in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, 42, (<-result).Value)
+
+	restarted := New[int](WithPersistence(path, 0, nil))
+	value, ok := restarted.Peek("slow")
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+}
+
+func TestMemoizerShutdownAndPersistTimeout(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	started := make(chan struct{})
+	memoizer.MemoizeDoChan("slow", func() (int, error) {
+		close(started)
+		<-release
+		return 42, nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, memoizer.ShutdownAndPersist(ctx), context.DeadlineExceeded)
+}
+
+func TestMemoizerShutdownAndPersistWaitsForBackgroundComputations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	memoizer := New[int](WithPersistence(path, 0, nil))
+
+	// The call returns before its background computation starts; shutdown still waits for it.
+	_, err := memoizer.Memoize("background", func() (int, error) {
+		time.Sleep(20 * time.Millisecond)
+		return 42, nil
+	}, WithEagerBackgroundFill())
+	require.NoError(t, err)
+	require.NoError(t, memoizer.ShutdownAndPersist(context.Background()))
+
+	value, ok := New[int](WithPersistence(path, 0, nil)).Peek("background")
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+}
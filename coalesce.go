@@ -0,0 +1,62 @@
+package memoizer
+
+import (
+	"sync"
+	"time"
+)
+
+// invalidationCoalescer delays the invalidations of WithCoalescedInvalidation to the end of a
+// window, collapsing those of the same key within it into one.
+type invalidationCoalescer struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	closed  bool
+	pending map[string]*time.Timer
+}
+
+// schedule arranges for apply to be called with key at the end of the window, unless it
+// already is. It reports false, and schedules nothing, once the coalescer is closed, in which
+// case the caller should apply the invalidation right away.
+func (c *invalidationCoalescer) schedule(key string, apply func(key string)) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
+	if _, ok := c.pending[key]; ok {
+		return true
+	}
+	if c.pending == nil {
+		c.pending = make(map[string]*time.Timer)
+	}
+	c.pending[key] = time.AfterFunc(c.window, func() {
+		if c.take(key) {
+			apply(key)
+		}
+	})
+	return true
+}
+
+// take reports whether the invalidation of key is still pending, and marks it as applied.
+func (c *invalidationCoalescer) take(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.pending[key]
+	delete(c.pending, key)
+	return ok
+}
+
+// close applies the pending invalidations right away, and stops coalescing further ones.
+func (c *invalidationCoalescer) close(apply func(key string)) {
+	c.mu.Lock()
+	c.closed = true
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	for key, timer := range pending {
+		timer.Stop()
+		apply(key)
+	}
+}
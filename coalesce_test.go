@@ -0,0 +1,58 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithCoalescedInvalidation(t *testing.T) {
+	memoizer := New[int](WithCoalescedInvalidation(50 * time.Millisecond))
+	computations := 0
+	compute := func() (int, error) {
+		computations++
+		return computations, nil
+	}
+
+	_, err := memoizer.Memoize("key", compute)
+	require.NoError(t, err)
+
+	// A burst of invalidations within the window.
+	for i := 0; i < 10; i++ {
+		memoizer.Delete("key")
+		result, err := memoizer.Memoize("key", compute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result, "the entry should be served until the window ends")
+	}
+
+	require.Eventually(t, func() bool {
+		_, ok := memoizer.Peek("key")
+		return !ok
+	}, time.Second, 10*time.Millisecond, "the invalidations should be applied at the end of the window")
+	result, err := memoizer.Memoize("key", compute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result)
+	assert.Equal(t, 2, computations, "the burst should cause a single recomputation")
+	assert.Equal(t, 50*time.Millisecond, memoizer.Config().CoalescedInvalidationWindow)
+}
+
+func TestMemoizerWithCoalescedInvalidationClose(t *testing.T) {
+	memoizer := New[int](WithCoalescedInvalidation(time.Hour))
+	memoizer.Set("key", 1, NoExpiration)
+
+	memoizer.Delete("key")
+	_, ok := memoizer.Peek("key")
+	require.True(t, ok)
+
+	require.NoError(t, memoizer.Close())
+	_, ok = memoizer.Peek("key")
+	assert.False(t, ok, "Close should apply the pending deletion")
+
+	// Once closed, deletions are applied right away.
+	memoizer.Set("key", 2, NoExpiration)
+	memoizer.Delete("key")
+	_, ok = memoizer.Peek("key")
+	assert.False(t, ok)
+}
@@ -0,0 +1,134 @@
+package memoizer
+
+import "time"
+
+// MemoizerConfig summarizes the effective configuration of a Memoizer, as reported by Config.
+type MemoizerConfig struct {
+	// DefaultExpiration is the expiration of cached results unless overridden per call,
+	// or NoExpiration.
+	DefaultExpiration time.Duration
+	// CleanupInterval is how often expired entries are purged, or zero if they are only
+	// purged when overwritten.
+	CleanupInterval time.Duration
+	// MaxEntries is the number of entries the store is bounded to, or zero if it is not an
+	// LRUStore with a bound.
+	MaxEntries int
+	// MaxCost is the total cost of entries the store is bounded to, or zero if it is not an
+	// LRUStore with a cost bound.
+	MaxCost int64
+
+	// GlobalCircuit reports whether a global circuit breaker is installed; the remaining
+	// GlobalCircuit fields describe it.
+	GlobalCircuit            bool
+	GlobalCircuitErrorRate   float64
+	GlobalCircuitWindow      time.Duration
+	GlobalCircuitCooldown    time.Duration
+	GlobalCircuitMinRequests int
+	// CircuitBreakerFailures is the number of consecutive failures of a key that open its
+	// circuit breaker, or zero if per-key circuit breakers are not installed;
+	// CircuitBreakerCooldown is how long the breaker stays open.
+	CircuitBreakerFailures int
+	CircuitBreakerCooldown time.Duration
+
+	// MaxRecursionDepth is the maximum nesting of computations, or zero if unbounded.
+	MaxRecursionDepth int
+	// FlexibleDecode reports whether a decoder for values not of type T is installed.
+	FlexibleDecode bool
+	// Recording reports whether cache operations are being captured by a Recorder.
+	Recording bool
+	// WindowedStats reports whether rolling-window hit and miss counts are kept.
+	WindowedStats bool
+	// ComputeObserver reports whether computations are reported to an observer.
+	ComputeObserver bool
+	// CallTracing reports whether cache hits and misses are reported to a CallTracer.
+	CallTracing bool
+	// Hooks reports whether lifecycle hooks are installed.
+	Hooks bool
+	// Logging reports whether debug logs are emitted to a logger.
+	Logging bool
+	// PersistencePath is the file the cache is persisted to, or empty if persistence is not
+	// configured; PersistenceInterval is how often it is written.
+	PersistencePath     string
+	PersistenceInterval time.Duration
+	// Broadcasting reports whether invalidations are shared with other processes.
+	Broadcasting bool
+	// MaxConcurrentComputations is the number of computations that may run at once, or zero
+	// if unbounded.
+	MaxConcurrentComputations int
+	// MaxErrorEntries is the number of cached errors held at most, or zero if it is
+	// DefaultMaxErrorEntries.
+	MaxErrorEntries int
+	// MaxTagsPerEntry is the number of tags attached to each entry at most, and
+	// MaxDistinctTags the number of distinct tags in use at once, or zero if unbounded.
+	MaxTagsPerEntry int
+	MaxDistinctTags int
+	// StrictTypes reports whether cached values of the wrong type panic instead of missing.
+	StrictTypes bool
+	// KeyHashing reports whether keys are transformed before they are stored.
+	KeyHashing bool
+	// OptionValidation reports whether calls validate their options.
+	OptionValidation bool
+	// MutationDetection reports whether cached values are verified against mutations.
+	MutationDetection bool
+	// CoalescedInvalidationWindow is the window within which deletions of a key are collapsed
+	// into one, or zero if they are applied right away.
+	CoalescedInvalidationWindow time.Duration
+	// DistributedLockTTL is how long distributed locks are held at most, or zero if no
+	// distributed lock is installed.
+	DistributedLockTTL time.Duration
+}
+
+// Config returns a copy of the Memoizer's effective configuration, for support and debugging.
+func (m *Memoizer[T]) Config() MemoizerConfig {
+	config := MemoizerConfig{
+		DefaultExpiration: m.defaultExpiration,
+		CleanupInterval:   m.cleanupInterval,
+		MaxRecursionDepth: m.reentrancy.maxDepth,
+		FlexibleDecode:    m.decode != nil,
+		Recording:         m.recorder != nil,
+		WindowedStats:     m.windowedStats != nil,
+		ComputeObserver:   m.observeCompute != nil,
+		CallTracing:       m.tracer != nil,
+		Hooks:             m.hooks.OnHit != nil || m.hooks.OnMiss != nil || m.hooks.OnEvict != nil || m.hooks.OnError != nil,
+		Logging:           m.logger != nil,
+		Broadcasting:      m.broadcaster != nil,
+		MutationDetection: m.mutations != nil,
+		StrictTypes:       m.strictTypes,
+		OptionValidation:  m.validateOptions,
+		KeyHashing:        m.keyHasher != nil,
+		MaxTagsPerEntry:   m.tags.maxPerEntry,
+		MaxDistinctTags:   m.tags.maxDistinct,
+	}
+	if m.errors.max > 0 {
+		config.MaxErrorEntries = m.errors.max
+	}
+	if store, ok := m.store.(*LRUStore); ok {
+		config.MaxEntries = store.maxEntries
+		config.MaxCost = store.maxCost
+	}
+	if m.lock != nil {
+		config.DistributedLockTTL = m.lock.ttl
+	}
+	if m.persistence != nil {
+		config.PersistencePath = m.persistence.path
+		config.PersistenceInterval = m.persistence.interval
+	}
+	if m.computations != nil {
+		config.MaxConcurrentComputations = cap(m.computations)
+	}
+	if m.coalescer != nil {
+		config.CoalescedInvalidationWindow = m.coalescer.window
+	}
+	if m.keyCircuits != nil {
+		config.CircuitBreakerFailures = m.keyCircuits.failures
+		config.CircuitBreakerCooldown = m.keyCircuits.cooldown
+	}
+	if m.circuit != nil {
+		config.GlobalCircuit = true
+		config.GlobalCircuitErrorRate = m.circuit.errorRate
+		config.GlobalCircuitWindow = m.circuit.window
+		config.GlobalCircuitCooldown = m.circuit.cooldown
+		config.GlobalCircuitMinRequests = m.circuit.minRequests
+	}
+	return config
+}
@@ -0,0 +1,37 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	assert.Equal(t, MemoizerConfig{DefaultExpiration: NoExpiration}, NewMemoizer[int]().Config())
+	assert.Equal(t, MemoizerConfig{DefaultExpiration: time.Minute}, NewMemoizerWithCacheExpiration[int](time.Minute).Config())
+
+	memoizer := NewMemoizerWithOptions[int](
+		WithGlobalCircuit(0.5, time.Minute, 10*time.Second),
+		WithMaxRecursionDepth(8),
+		WithFlexibleDecode(func(interface{}) (int, bool) { return 0, false }),
+		WithRecorder(NewRecorder()),
+		WithWindowedStats(),
+		WithMaxEntries(100),
+		WithMaxErrorEntries(10),
+	)
+	assert.Equal(t, MemoizerConfig{
+		DefaultExpiration:        NoExpiration,
+		MaxEntries:               100,
+		MaxErrorEntries:          10,
+		GlobalCircuit:            true,
+		GlobalCircuitErrorRate:   0.5,
+		GlobalCircuitWindow:      time.Minute,
+		GlobalCircuitCooldown:    10 * time.Second,
+		GlobalCircuitMinRequests: DefaultCircuitMinRequests,
+		MaxRecursionDepth:        8,
+		FlexibleDecode:           true,
+		Recording:                true,
+		WindowedStats:            true,
+	}, memoizer.Config())
+}
@@ -0,0 +1,114 @@
+package memoizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantKey struct{}
+
+func TestMemoizeContext(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	callCount := 0
+
+	augmenter := WithContextKeyAugmenter(func(ctx context.Context, key string) string {
+		return ctx.Value(tenantKey{}).(string) + ":" + key
+	})
+	load := func(ctx context.Context) (string, error) {
+		callCount++
+		return "settings for " + ctx.Value(tenantKey{}).(string), nil
+	}
+
+	ctxA := context.WithValue(context.Background(), tenantKey{}, "a")
+	ctxB := context.WithValue(context.Background(), tenantKey{}, "b")
+
+	resultA, err := memoizer.MemoizeContext(ctxA, "settings", load, augmenter)
+	require.NoError(t, err)
+	assert.Equal(t, "settings for a", resultA)
+
+	resultB, err := memoizer.MemoizeContext(ctxB, "settings", load, augmenter)
+	require.NoError(t, err)
+	assert.Equal(t, "settings for b", resultB)
+	assert.Equal(t, 2, callCount, "different contexts should produce distinct cache entries")
+
+	resultA, err = memoizer.MemoizeContext(ctxA, "settings", load, augmenter)
+	require.NoError(t, err)
+	assert.Equal(t, "settings for a", resultA)
+	assert.Equal(t, 2, callCount, "cached value should be used")
+
+	_, cachedA := memoizer.store.Get("a:settings")
+	_, cachedB := memoizer.store.Get("b:settings")
+	assert.True(t, cachedA)
+	assert.True(t, cachedB)
+}
+
+func TestMemoizeContextCancellation(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := memoizer.MemoizeContext(ctx, "key", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+			return 42, nil
+		})
+		done <- err
+	}()
+
+	<-started
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled, "a cancelled caller should stop waiting")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the cancelled caller")
+	}
+
+	// The computation keeps running and is cached for other callers.
+	close(release)
+	result, err := memoizer.MemoizeContext(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+
+	// An already-cancelled context fails fast.
+	_, err = memoizer.MemoizeContext(ctx, "other", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMemoizeContextWithDetachedContext(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), tenantKey{}, "a"), time.Millisecond)
+	defer cancel()
+
+	result, err := memoizer.MemoizeContext(ctx, "key", func(ctx context.Context) (string, error) {
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		assert.Nil(t, ctx.Done())
+		return ctx.Value(tenantKey{}).(string), nil
+	}, WithDetachedContext())
+	require.NoError(t, err)
+	assert.Equal(t, "a", result, "a detached context should keep the caller's values")
+}
+
+func TestMemoizeContextPanic(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	customErr := customError{message: "custom panic error"}
+
+	assert.PanicsWithValue(t, customErr, func() {
+		_, _ = memoizer.MemoizeContext(context.Background(), "key", func(ctx context.Context) (int, error) {
+			panic(customErr)
+		})
+	})
+}
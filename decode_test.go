@@ -0,0 +1,45 @@
+package memoizer
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithFlexibleDecode(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithFlexibleDecode(func(raw interface{}) (int, bool) {
+		b, ok := raw.([]byte)
+		if !ok {
+			return 0, false
+		}
+		n, err := strconv.Atoi(string(b))
+		return n, err == nil
+	}))
+
+	// Simulate a backend holding a mix of typed and serialized entries.
+	memoizer.store.Set("typed", 1, NoExpiration)
+	memoizer.store.Set("serialized", []byte("2"), NoExpiration)
+	memoizer.store.Set("garbage", "three", NoExpiration)
+
+	notCalled := func() (int, error) {
+		return 0, errors.New("this should not be called")
+	}
+
+	result, err := memoizer.Memoize("typed", notCalled)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	result, err = memoizer.Memoize("serialized", notCalled)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result)
+
+	// Undecodable values are treated as misses and recomputed.
+	result, err = memoizer.Memoize("garbage", func() (int, error) {
+		return 3, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result)
+}
@@ -0,0 +1,42 @@
+package memoizer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// defaultMemoizers holds the package-level Memoizers used by Do, one per result type.
+var defaultMemoizers struct {
+	mu        sync.Mutex
+	memoizers map[reflect.Type]interface{}
+}
+
+// Default returns the package-level Memoizer for results of type T, creating it with New on
+// first use. Every caller asking for the same T shares it, so keys should be namespaced, for
+// example with Group, by the packages using it.
+func Default[T any]() *Memoizer[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	defaultMemoizers.mu.Lock()
+	defer defaultMemoizers.mu.Unlock()
+	if m, ok := defaultMemoizers.memoizers[typ]; ok {
+		return m.(*Memoizer[T])
+	}
+	if defaultMemoizers.memoizers == nil {
+		defaultMemoizers.memoizers = make(map[reflect.Type]interface{})
+	}
+	m := New[T]()
+	defaultMemoizers.memoizers[typ] = m
+	return m
+}
+
+// Do is Memoize on the package-level Memoizer for results of type T, for scripts and small
+// services that would rather not pass a Memoizer around. Its results never expire unless
+// options say otherwise.
+//
+// Example usage:
+//
+//	config, err := memoizer.Do("config", loadConfig, memoizer.WithTTL(time.Minute))
+func Do[T any](key string, fn func() (T, error), options ...Option) (T, error) {
+	return Default[T]().Memoize(key, fn, options...)
+}
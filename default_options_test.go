@@ -0,0 +1,48 @@
+package memoizer
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultCallOptionsApplyToEveryCall(t *testing.T) {
+	m := New[[]int](WithDefaultCallOptions(
+		WithCachePredicate(func(result []int) bool { return len(result) > 0 }),
+		WithRetry(3, nil),
+	))
+
+	var calls atomic.Int32
+	value, err := m.Memoize("key", func() ([]int, error) {
+		if calls.Add(1) < 3 {
+			return nil, errors.New("transient")
+		}
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, value)
+	assert.EqualValues(t, 3, calls.Load(), "the default retry should apply")
+	_, ok := m.Peek("key")
+	assert.False(t, ok, "the default predicate should apply")
+}
+
+func TestCallOptionsOverrideDefaults(t *testing.T) {
+	m := New[int](WithDefaultCallOptions(
+		WithExpiration(func(interface{}) time.Duration { return time.Hour }),
+	))
+	fn := func() (int, error) { return 1, nil }
+
+	_, err := m.Memoize("default", fn)
+	require.NoError(t, err)
+	_, err = m.Memoize("override", fn, WithExpiration(func(interface{}) time.Duration { return time.Minute }))
+	require.NoError(t, err)
+
+	inventory := m.Inventory()
+	require.Len(t, inventory, 2)
+	assert.InDelta(t, float64(time.Hour), float64(inventory[0].RemainingTTL), float64(time.Second))
+	assert.InDelta(t, float64(time.Minute), float64(inventory[1].RemainingTTL), float64(time.Second))
+}
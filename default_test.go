@@ -0,0 +1,35 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type defaultTestResult struct{ N int }
+
+func TestDo(t *testing.T) {
+	// The default Memoizers outlive the test, such as when it is run with -count.
+	Default[defaultTestResult]().Flush()
+	calls := 0
+	fn := func() (defaultTestResult, error) {
+		calls++
+		return defaultTestResult{N: 42}, nil
+	}
+	for i := 0; i < 2; i++ {
+		value, err := Do("key", fn)
+		require.NoError(t, err)
+		assert.Equal(t, 42, value.N)
+	}
+	assert.Equal(t, 1, calls)
+	assert.Same(t, Default[defaultTestResult](), Default[defaultTestResult]())
+
+	// Each result type has its own Memoizer.
+	_, err := Do("key", func() (*defaultTestResult, error) { return nil, errors.New("failed") })
+	assert.Error(t, err)
+	value, ok := Default[defaultTestResult]().Peek("key")
+	assert.True(t, ok)
+	assert.Equal(t, 42, value.N)
+}
@@ -0,0 +1,121 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerDelete(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	var calls int
+	fn := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	_, err := memoizer.Memoize("key", fn)
+	require.NoError(t, err)
+	_, err = memoizer.Memoize("other", fn)
+	require.NoError(t, err)
+
+	memoizer.Delete("key")
+
+	result, err := memoizer.Memoize("key", fn)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result, "a deleted entry should be recomputed")
+	result, err = memoizer.Memoize("other", fn)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result, "other entries should be kept")
+
+	// Deleting a missing key is a no-op.
+	memoizer.Delete("missing")
+}
+
+func TestMemoizerDeleteForgetsInFlightComputation(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = memoizer.Memoize("key", func() (string, error) {
+			close(started)
+			<-release
+			return "before", nil
+		})
+	}()
+	<-started
+
+	memoizer.Delete("key")
+
+	result, err := memoizer.Memoize("key", func() (string, error) {
+		return "after", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "after", result)
+	close(release)
+	<-done
+}
+
+func TestMemoizerDeleteCachedError(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	_, err := memoizer.Memoize("key", func() (int, error) { return 0, errors.New("failed") }, WithErrorCaching(time.Minute))
+	require.Error(t, err)
+
+	memoizer.Delete("key")
+
+	result, err := memoizer.Memoize("key", func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestMemoizerFlush(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	memoizer.SetAll(map[string]int{"a": 1, "b": 2})
+	_, err := memoizer.Memoize("c", func() (int, error) { return 0, errors.New("failed") }, WithErrorCaching(time.Minute))
+	require.Error(t, err)
+
+	memoizer.Flush()
+
+	assert.Empty(t, memoizer.Inventory())
+	for _, key := range []string{"a", "b", "c"} {
+		result, err := memoizer.Memoize(key, func() (int, error) { return 42, nil })
+		require.NoError(t, err)
+		assert.Equal(t, 42, result, key)
+	}
+}
+
+func TestMemoizerDeletePrefix(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	memoizer.SetAll(map[string]int{"user:123:name": 1, "user:123:email": 2, "user:1234:name": 3, "org:7": 4})
+
+	memoizer.DeletePrefix("user:123:")
+
+	entries := memoizer.store.(InspectableStore).Entries()
+	assert.NotContains(t, entries, "user:123:name")
+	assert.NotContains(t, entries, "user:123:email")
+	assert.Contains(t, entries, "user:1234:name")
+	assert.Contains(t, entries, "org:7")
+}
+
+func TestMemoizerDeleteMatch(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithStore(newMapStore()))
+	memoizer.SetAll(map[string]int{"a1": 1, "a2": 2, "b1": 3})
+	_, err := memoizer.Memoize("a3", func() (int, error) { return 0, errors.New("failed") }, WithErrorCaching(time.Minute))
+	require.Error(t, err)
+
+	// Without an InspectableStore, the keys stored by the Memoizer are matched.
+	memoizer.DeleteMatch(func(key string) bool {
+		return key[0] == 'a'
+	})
+
+	for key, want := range map[string]int{"a1": 42, "a2": 42, "a3": 42, "b1": 3} {
+		result, err := memoizer.Memoize(key, func() (int, error) { return 42, nil })
+		require.NoError(t, err)
+		assert.Equal(t, want, result, key)
+	}
+}
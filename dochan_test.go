@@ -0,0 +1,83 @@
+package memoizer
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizeDoChan(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	var invocations int32
+	release := make(chan struct{})
+
+	const callers = 5
+	channels := make([]<-chan Result[int], callers)
+	for i := range channels {
+		channels[i] = memoizer.MemoizeDoChan("key", func() (int, error) {
+			atomic.AddInt32(&invocations, 1)
+			<-release
+			return 100, nil
+		})
+	}
+	close(release)
+
+	for _, ch := range channels {
+		select {
+		case r := <-ch:
+			require.NoError(t, r.Err)
+			assert.Equal(t, 100, r.Value)
+			assert.True(t, r.Shared)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for result")
+		}
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&invocations), "callers should share a single computation")
+
+	// The result is cached.
+	r := <-memoizer.MemoizeDoChan("key", func() (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, r.Err)
+	assert.Equal(t, 100, r.Value)
+}
+
+func TestMemoizeDoChanPanic(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	customErr := customError{message: "custom panic error"}
+
+	r := <-memoizer.MemoizeDoChan("panic_key", func() (int, error) {
+		panic(customErr)
+	})
+	var panicErr *PanicError
+	require.ErrorAs(t, r.Err, &panicErr)
+	assert.Equal(t, customErr, panicErr.Value)
+	assert.ErrorIs(t, r.Err, customErr)
+}
+
+func TestMemoizeAsync(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	release := make(chan struct{})
+
+	ch := memoizer.MemoizeAsync("key", func() (int, error) {
+		<-release
+		return 100, nil
+	})
+	select {
+	case <-ch:
+		t.Fatal("result delivered before the computation finished")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(release)
+
+	r := <-ch
+	require.NoError(t, r.Err)
+	assert.Equal(t, 100, r.Value)
+	value, ok := memoizer.Peek("key")
+	assert.True(t, ok)
+	assert.Equal(t, 100, value)
+}
@@ -0,0 +1,57 @@
+package memoizer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithEagerBackgroundFill(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	release := make(chan struct{})
+
+	result, err := memoizer.Memoize("key", func() (int, error) {
+		<-release
+		return 42, nil
+	}, WithEagerBackgroundFill())
+	require.NoError(t, err)
+	assert.Equal(t, 0, result, "a cold miss should return the zero value immediately")
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		_, ok := memoizer.store.Get("key")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	result, err = memoizer.Memoize("key", func() (int, error) {
+		return 0, nil
+	}, WithEagerBackgroundFill())
+	require.NoError(t, err)
+	assert.Equal(t, 42, result, "subsequent calls should hit the background-filled value")
+}
+
+func TestMemoizeContextWithEagerBackgroundFill(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	fillErr := make(chan error, 1)
+
+	_, err := memoizer.MemoizeContext(ctx, "key", func(ctx context.Context) (int, error) {
+		<-release
+		fillErr <- ctx.Err()
+		return 42, nil
+	}, WithEagerBackgroundFill())
+	require.NoError(t, err)
+
+	// The caller is done with its context once the call returns.
+	cancel()
+	close(release)
+	assert.NoError(t, <-fillErr, "the background fill should not be cancelled with the caller")
+	assert.Eventually(t, func() bool {
+		_, ok := memoizer.Peek("key")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+}
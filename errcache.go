@@ -0,0 +1,132 @@
+package memoizer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultMaxErrorEntries is the number of errors cached by WithErrorCaching that a Memoizer
+// holds at most, unless configured otherwise with WithMaxErrorEntries, so that a flood of
+// unique failing keys cannot grow the error cache without bound.
+const DefaultMaxErrorEntries = 10000
+
+// cachedError is an error recorded by WithErrorCaching, along with when it expires.
+type cachedError struct {
+	key       string
+	err       error
+	expiresAt time.Time
+}
+
+// expired reports whether the error has expired at now.
+func (e *cachedError) expired(now time.Time) bool {
+	return now.After(e.expiresAt)
+}
+
+// errorCache holds the errors recorded by WithErrorCaching. It is kept in process, next to the
+// Store, so that the original error values are returned as-is regardless of how the Store
+// serializes results. It holds at most max errors, or DefaultMaxErrorEntries if max is not
+// positive: once full, it drops the expired errors, and then the least recently used ones.
+type errorCache struct {
+	mu     sync.Mutex
+	max    int
+	errors map[string]*list.Element
+	order  *list.List // of *cachedError, most recently used first
+}
+
+// limit returns the number of errors the cache holds at most.
+func (c *errorCache) limit() int {
+	if c.max > 0 {
+		return c.max
+	}
+	return DefaultMaxErrorEntries
+}
+
+// set records err for key until ttl elapses.
+func (c *errorCache) set(key string, err error, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.errors == nil {
+		c.errors = make(map[string]*list.Element)
+		c.order = list.New()
+	}
+	now := time.Now()
+	if element, ok := c.errors[key]; ok {
+		cached := element.Value.(*cachedError)
+		cached.err, cached.expiresAt = err, now.Add(ttl)
+		c.order.MoveToFront(element)
+		return
+	}
+	if len(c.errors) >= c.limit() {
+		c.purgeExpired(now)
+	}
+	for len(c.errors) >= c.limit() {
+		c.removeElement(c.order.Back())
+	}
+	c.errors[key] = c.order.PushFront(&cachedError{key: key, err: err, expiresAt: now.Add(ttl)})
+}
+
+// purgeExpired drops every expired error. The caller must hold c.mu.
+func (c *errorCache) purgeExpired(now time.Time) {
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*cachedError).expired(now) {
+			c.removeElement(element)
+		}
+		element = next
+	}
+}
+
+// removeElement drops the error held by element. The caller must hold c.mu.
+func (c *errorCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.errors, element.Value.(*cachedError).key)
+}
+
+// get returns the unexpired error recorded for key, if any, and marks it as recently used.
+func (c *errorCache) get(key string) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.errors[key]
+	if !ok {
+		return nil, false
+	}
+	cached := element.Value.(*cachedError)
+	if cached.expired(time.Now()) {
+		c.removeElement(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return cached.err, true
+}
+
+// remove forgets the error recorded for key.
+func (c *errorCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.errors[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// keys returns the keys that have an unexpired error recorded.
+func (c *errorCache) keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	keys := make([]string, 0, len(c.errors))
+	for key, element := range c.errors {
+		if !element.Value.(*cachedError).expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// reset forgets every recorded error.
+func (c *errorCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errors = nil
+	c.order = nil
+}
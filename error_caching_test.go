@@ -0,0 +1,173 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithErrorCaching(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	upstreamErr := &customError{"upstream unavailable"}
+	var calls int
+	fn := func() (int, error) {
+		calls++
+		return 0, upstreamErr
+	}
+
+	_, err := memoizer.Memoize("key", fn, WithErrorCaching(50*time.Millisecond))
+	require.Error(t, err)
+
+	// The cached error is returned as the original value without calling fn again.
+	_, err = memoizer.Memoize("key", fn, WithErrorCaching(50*time.Millisecond))
+	assert.Same(t, upstreamErr, err)
+	result := <-memoizer.MemoizeDoChan("key", fn)
+	assert.Same(t, upstreamErr, result.Err)
+	assert.Equal(t, 1, calls)
+
+	// Once the error expires, the function runs again.
+	time.Sleep(70 * time.Millisecond)
+	_, err = memoizer.Memoize("key", fn, WithErrorCaching(50*time.Millisecond))
+	assert.Same(t, upstreamErr, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestMemoizerWithErrorCachingFunc(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	errNotFound := errors.New("not found")
+	errTimeout := errors.New("timeout")
+	option := WithErrorCachingFunc(func(err error) time.Duration {
+		if errors.Is(err, errNotFound) {
+			return time.Minute
+		}
+		return 0
+	})
+
+	var calls int
+	fail := func(err error) func() (int, error) {
+		return func() (int, error) {
+			calls++
+			return 0, err
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := memoizer.Memoize("missing", fail(errNotFound), option)
+		assert.ErrorIs(t, err, errNotFound)
+		_, err = memoizer.Memoize("slow", fail(errTimeout), option)
+		assert.ErrorIs(t, err, errTimeout)
+	}
+	assert.Equal(t, 3, calls, "only the not-found error should be cached")
+}
+
+func TestMemoizerErrorCachingClearedByStore(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	_, err := memoizer.Memoize("key", func() (int, error) { return 0, errors.New("failed") }, WithErrorCaching(time.Minute))
+	require.Error(t, err)
+
+	memoizer.SetAll(map[string]int{"key": 42})
+
+	result, err := memoizer.Memoize("key", func() (int, error) { return 0, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestMemoizerErrorsNotCachedByDefault(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	var calls int
+	fn := func() (int, error) {
+		calls++
+		return 0, errors.New("failed")
+	}
+
+	_, _ = memoizer.Memoize("key", fn)
+	_, _ = memoizer.Memoize("key", fn)
+	assert.Equal(t, 2, calls)
+}
+
+// upstreamError is an error carrying structured data, as returned by an API client.
+type upstreamError struct {
+	Status    int
+	RequestID string
+}
+
+func (e *upstreamError) Error() string {
+	return fmt.Sprintf("upstream returned %d (request %s)", e.Status, e.RequestID)
+}
+
+func TestMemoizerErrorCachingPreservesErrorTypes(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	calls := 0
+	fn := func() (int, error) {
+		calls++
+		return 0, fmt.Errorf("fetching user: %w", &upstreamError{Status: 503, RequestID: "req-42"})
+	}
+
+	_, err := memoizer.Memoize("user", fn, WithErrorCaching(time.Minute))
+	require.Error(t, err)
+	_, err = memoizer.Memoize("user", fn, WithErrorCaching(time.Minute))
+	require.Error(t, err)
+	assert.Equal(t, 1, calls, "the second call should be served the cached error")
+
+	var upstream *upstreamError
+	require.True(t, errors.As(err, &upstream), "the cached error should keep its concrete type")
+	assert.Equal(t, 503, upstream.Status)
+	assert.Equal(t, "req-42", upstream.RequestID)
+	assert.Equal(t, "fetching user: upstream returned 503 (request req-42)", err.Error())
+}
+
+func TestErrorCacheBounds(t *testing.T) {
+	cache := errorCache{max: 3}
+	failure := errors.New("failed")
+
+	cache.set("expired", failure, -time.Second)
+	assert.Empty(t, cache.keys(), "expired errors should not be listed")
+
+	// Expired errors make room first, then the least recently used ones.
+	cache.set("a", failure, time.Minute)
+	cache.set("b", failure, time.Minute)
+	cache.set("c", failure, time.Minute)
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, cache.keys())
+	_, ok := cache.get("a")
+	require.True(t, ok)
+	cache.set("d", failure, time.Minute)
+	assert.ElementsMatch(t, []string{"a", "c", "d"}, cache.keys())
+	assert.Len(t, cache.errors, 3)
+
+	// Without a configured bound, the default one applies.
+	var unbounded errorCache
+	for i := 0; i < DefaultMaxErrorEntries+10; i++ {
+		unbounded.set(fmt.Sprint(i), failure, time.Minute)
+	}
+	assert.Len(t, unbounded.errors, DefaultMaxErrorEntries)
+}
+
+func TestMemoizerWithMaxErrorEntries(t *testing.T) {
+	memoizer := New[int](WithMaxEntries(100), WithMaxErrorEntries(3))
+	for i := 0; i < 10; i++ {
+		_, err := memoizer.Memoize(fmt.Sprint("ok", i), func() (int, error) { return i, nil })
+		require.NoError(t, err)
+	}
+
+	// An outage produces many unique failures.
+	failure := errors.New("outage")
+	for i := 0; i < 50; i++ {
+		_, err := memoizer.Memoize(fmt.Sprint("failing", i), func() (int, error) { return 0, failure }, WithErrorCaching(time.Minute))
+		require.ErrorIs(t, err, failure)
+	}
+
+	assert.Len(t, memoizer.errors.keys(), 3, "cached errors should respect their own bound")
+	for i := 0; i < 10; i++ {
+		value, ok := memoizer.Peek(fmt.Sprint("ok", i))
+		assert.True(t, ok, "cached errors should not evict successful results")
+		assert.Equal(t, i, value)
+	}
+	// The most recent errors are kept.
+	_, err := memoizer.Memoize("failing49", func() (int, error) { return 1, nil }, WithErrorCaching(time.Minute))
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 3, memoizer.Config().MaxErrorEntries)
+}
@@ -0,0 +1,135 @@
+package memoizer
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Codec serializes the data exported by Export, such as to persist the cache across restarts.
+// The stores of packages memobolt, memoredis and memopeer use it to serialize values too.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is a Codec that uses encoding/json. It is the default.
+type JSONCodec struct{}
+
+// Marshal implements Codec.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec is a Codec that uses encoding/gob. Concrete types stored in an interface-typed T
+// must be registered with gob.Register.
+type GobCodec struct{}
+
+// Marshal implements Codec.
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal implements Codec.
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// exportVersion identifies the layout of exported data.
+const exportVersion = 1
+
+// exported is the data serialized by Export.
+type exported[T any] struct {
+	Version int
+	Entries []exportedEntry[T]
+}
+
+// exportedEntry is a cached entry serialized by Export.
+type exportedEntry[T any] struct {
+	Key   string
+	Value T
+	// ExpiresAt is the time the entry expires, or the zero time if it never expires.
+	ExpiresAt time.Time
+	Tags      []string `json:",omitempty"`
+}
+
+// Export serializes every unexpired entry, along with its expiration time and tags, with the
+// codec configured by WithCodec, so that the cache can be persisted on shutdown and brought
+// back with Restore at startup. It requires a store that implements InspectableStore, and
+// exports no entries otherwise. Unlike Snapshot, which is an in-memory view for paging, Export
+// produces bytes.
+func (m *Memoizer[T]) Export() ([]byte, error) {
+	return m.export(m.codec)
+}
+
+// export implements Export with the given codec.
+func (m *Memoizer[T]) export(codec Codec) ([]byte, error) {
+	items := m.entries()
+	data := exported[T]{Version: exportVersion, Entries: make([]exportedEntry[T], 0, len(items))}
+	for key, item := range items {
+		value, ok := m.typed(item.Value)
+		if !ok {
+			continue
+		}
+		// An entry kept beyond its expiration for a stale window is exported as expiring
+		// when it goes stale.
+		expiresAt := m.access.freshness(key).staleAt
+		if expiresAt.IsZero() {
+			expiresAt = item.ExpiresAt
+		}
+		data.Entries = append(data.Entries, exportedEntry[T]{
+			Key:       key,
+			Value:     value,
+			ExpiresAt: expiresAt,
+			Tags:      m.tags.tags(key),
+		})
+	}
+	sort.Slice(data.Entries, func(i, j int) bool {
+		return data.Entries[i].Key < data.Entries[j].Key
+	})
+	return codec.Marshal(data)
+}
+
+// Restore stores the entries serialized by Export, with the codec configured by WithCodec.
+// Each entry keeps its original expiration time and tags; entries that have expired since they
+// were exported are skipped. Restored entries replace any cached under the same keys, and
+// other entries are kept.
+func (m *Memoizer[T]) Restore(data []byte) error {
+	return m.restore(data, m.codec)
+}
+
+// restore implements Restore with the given codec.
+func (m *Memoizer[T]) restore(data []byte, codec Codec) error {
+	var restored exported[T]
+	if err := codec.Unmarshal(data, &restored); err != nil {
+		return fmt.Errorf("memoizer: restore: %w", err)
+	}
+	if restored.Version != exportVersion {
+		return fmt.Errorf("memoizer: restore: unsupported version %d", restored.Version)
+	}
+	now := time.Now()
+	for _, entry := range restored.Entries {
+		ttl := NoExpiration
+		if !entry.ExpiresAt.IsZero() {
+			if ttl = entry.ExpiresAt.Sub(now); ttl <= 0 {
+				continue
+			}
+		}
+		opts := fixedExpiration[T](ttl)
+		opts.tags = entry.Tags
+		m.put(entry.Key, entry.Value, opts)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerExportRestore(t *testing.T) {
+	for name, codec := range map[string]Codec{"json": JSONCodec{}, "gob": GobCodec{}} {
+		t.Run(name, func(t *testing.T) {
+			source := NewMemoizerWithOptions[point](WithCodec(codec))
+			source.Set("a", point{1, 2}, time.Minute)
+			source.Set("b", point{3, 4}, NoExpiration)
+			source.SetAll(map[string]point{"c": {5, 6}}, WithTags("tag"))
+			source.Set("expiring", point{7, 8}, 10*time.Millisecond)
+
+			data, err := source.Export()
+			require.NoError(t, err)
+			time.Sleep(20 * time.Millisecond)
+
+			target := NewMemoizerWithOptions[point](WithCodec(codec))
+			target.Set("b", point{0, 0}, NoExpiration)
+			target.Set("other", point{9, 9}, NoExpiration)
+			require.NoError(t, target.Restore(data))
+
+			snapshot := target.Snapshot()
+			assert.Equal(t, []SnapshotEntry[point]{
+				{Key: "a", Value: point{1, 2}},
+				{Key: "b", Value: point{3, 4}},
+				{Key: "c", Value: point{5, 6}},
+				{Key: "other", Value: point{9, 9}},
+			}, snapshot.Page(0, 10))
+
+			_, expiresAt, _ := target.store.(InspectableStore).GetWithExpiration("a")
+			assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+			_, expiresAt, _ = target.store.(InspectableStore).GetWithExpiration("b")
+			assert.True(t, expiresAt.IsZero())
+
+			target.InvalidateTag("tag")
+			_, ok := target.Peek("c")
+			assert.False(t, ok, "tags should be restored")
+		})
+	}
+}
+
+func TestMemoizerRestoreInvalidData(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	assert.Error(t, memoizer.Restore([]byte("not json")))
+	assert.ErrorContains(t, memoizer.Restore([]byte(`{"Version":99}`)), "unsupported version")
+}
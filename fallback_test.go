@@ -0,0 +1,126 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithPanicFallback(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	fallback := WithPanicFallback(func() (int, error) {
+		return 7, nil
+	})
+
+	result, err := memoizer.Memoize("key", func() (int, error) {
+		panic("intentional panic")
+	}, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, 7, result, "a panicking primary should yield the fallback's value")
+
+	// The fallback value is not cached by default.
+	result, err = memoizer.Memoize("key", func() (int, error) {
+		return 42, nil
+	}, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestMemoizerWithCachedPanicFallback(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	result, err := memoizer.Memoize("key", func() (int, error) {
+		panic("intentional panic")
+	}, &PanicFallbackOption[int]{Fallback: func() (int, error) {
+		return 7, nil
+	}, Cache: true})
+	require.NoError(t, err)
+	assert.Equal(t, 7, result)
+
+	result, err = memoizer.Memoize("key", func() (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 7, result)
+}
+
+func TestMemoizerWithFailingPanicFallback(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	customErr := customError{message: "custom panic error"}
+
+	assert.PanicsWithValue(t, customErr, func() {
+		_, _ = memoizer.Memoize("key", func() (int, error) {
+			panic(customErr)
+		}, WithPanicFallback(func() (int, error) {
+			return 0, errors.New("fallback failed")
+		}))
+	}, "the original panic should propagate when the fallback fails")
+
+	assert.PanicsWithValue(t, customErr, func() {
+		_, _ = memoizer.Memoize("key", func() (int, error) {
+			panic(customErr)
+		}, WithPanicFallback(func() (int, error) {
+			panic("fallback panicked")
+		}))
+	}, "the original panic should propagate when the fallback panics")
+}
+
+func TestMemoizerWithFallback(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	failure := errors.New("backend down")
+	var fallbackKey string
+	var fallbackErr error
+	fallback := WithFallback(func(key string, err error) (int, error) {
+		fallbackKey, fallbackErr = key, err
+		return -1, nil
+	})
+
+	result, err := memoizer.Memoize("key", func() (int, error) {
+		return 0, failure
+	}, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, -1, result)
+	assert.Equal(t, "key", fallbackKey)
+	assert.ErrorIs(t, fallbackErr, failure)
+
+	// The fallback value is not cached by default.
+	result, err = memoizer.Memoize("key", func() (int, error) {
+		return 42, nil
+	}, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestMemoizerWithCachedFallback(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	result, err := memoizer.Memoize("key", func() (int, error) {
+		return 0, errors.New("backend down")
+	}, &FallbackOption[int]{Fallback: func(string, error) (int, error) {
+		return -1, nil
+	}, Cache: true})
+	require.NoError(t, err)
+	assert.Equal(t, -1, result)
+
+	result, err = memoizer.Memoize("key", func() (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, -1, result)
+}
+
+func TestMemoizerWithFailingFallback(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	failure := errors.New("backend down")
+
+	_, err := memoizer.Memoize("key", func() (int, error) {
+		return 0, failure
+	}, WithFallback(func(key string, err error) (int, error) {
+		return 0, fmt.Errorf("no fallback for %s: %w", key, err)
+	}))
+	assert.EqualError(t, err, "no fallback for key: backend down")
+	assert.ErrorIs(t, err, failure)
+}
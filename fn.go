@@ -0,0 +1,91 @@
+package memoizer
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// MemoizeFn1 returns a memoized version of fn, whose results are cached in m under a key
+// derived from the name of fn and its argument, so that callers need not build key strings
+// by hand. By default, arguments that print the same with the %#v verb of package fmt share
+// a cache entry; this compares pointers nested in arguments by address rather than by the
+// values they point to. WithKeyFunc(KeyOf) compares them by value instead. options apply to
+// every call.
+//
+// The key starts with the name of fn, so functions wrapped on the same Memoizer do not share
+// entries. Closures created by the same function literal share a name, and therefore
+// entries: give each of them its own Memoizer if they compute different results for the same
+// arguments.
+//
+// MemoizeFn1 is a function rather than a method because Go does not allow methods to have
+// type parameters of their own.
+//
+// Example usage:
+//
+//	getUser := memoizer.MemoizeFn1(users, fetchUser)
+//	user, err := getUser(42)
+func MemoizeFn1[A, T any](m *Memoizer[T], fn func(A) (T, error), options ...Option) func(A) (T, error) {
+	name, keyFunc := funcName(fn), argKeyFunc(options)
+	return func(a A) (T, error) {
+		return m.Memoize(argsKey(name, keyFunc, a), func() (T, error) {
+			return fn(a)
+		}, options...)
+	}
+}
+
+// MemoizeFn2 is like MemoizeFn1 for a function of two arguments.
+func MemoizeFn2[A, B, T any](m *Memoizer[T], fn func(A, B) (T, error), options ...Option) func(A, B) (T, error) {
+	name, keyFunc := funcName(fn), argKeyFunc(options)
+	return func(a A, b B) (T, error) {
+		return m.Memoize(argsKey(name, keyFunc, a, b), func() (T, error) {
+			return fn(a, b)
+		}, options...)
+	}
+}
+
+// MemoizeFn3 is like MemoizeFn1 for a function of three arguments.
+func MemoizeFn3[A, B, C, T any](m *Memoizer[T], fn func(A, B, C) (T, error), options ...Option) func(A, B, C) (T, error) {
+	name, keyFunc := funcName(fn), argKeyFunc(options)
+	return func(a A, b B, c C) (T, error) {
+		return m.Memoize(argsKey(name, keyFunc, a, b, c), func() (T, error) {
+			return fn(a, b, c)
+		}, options...)
+	}
+}
+
+// funcName returns the fully qualified name of the function fn.
+func funcName(fn interface{}) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return fmt.Sprintf("%p", fn)
+}
+
+// argKeyFunc returns the function configured by WithKeyFunc in options, or the default one.
+func argKeyFunc(options []Option) func(arg interface{}) string {
+	keyFunc := func(arg interface{}) string { return fmt.Sprintf("%#v", arg) }
+	for _, option := range options {
+		if opt, ok := option.(*KeyFuncOption); ok && opt.KeyFunc != nil {
+			keyFunc = opt.KeyFunc
+		}
+	}
+	return keyFunc
+}
+
+// argsKey derives the key of a call of the function called name with args.
+func argsKey(name string, keyFunc func(arg interface{}) string, args ...interface{}) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for i, arg := range args {
+		if i == 0 {
+			b.WriteByte('(')
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(keyFunc(arg))
+	}
+	b.WriteByte(')')
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package memoizer
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func square(n int) (int, error) {
+	return n * n, nil
+}
+
+func TestMemoizeFn1(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	calls := 0
+	double := MemoizeFn1(memoizer, func(n int) (int, error) {
+		calls++
+		return 2 * n, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		value, err := double(21)
+		require.NoError(t, err)
+		assert.Equal(t, 42, value)
+	}
+	value, err := double(5)
+	require.NoError(t, err)
+	assert.Equal(t, 10, value)
+	assert.Equal(t, 2, calls)
+
+	// Another function wrapped on the same Memoizer does not share its entries.
+	value, err = MemoizeFn1(memoizer, square)(5)
+	require.NoError(t, err)
+	assert.Equal(t, 25, value)
+	value, ok := memoizer.Peek("github.com/KevinWang15/memoizer.square(5)")
+	assert.True(t, ok)
+	assert.Equal(t, 25, value)
+}
+
+func TestMemoizeFn2And3(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	calls := 0
+	join := MemoizeFn2(memoizer, func(sep string, parts []string) (string, error) {
+		calls++
+		return strings.Join(parts, sep), nil
+	})
+	value, err := join(",", []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "a,b", value)
+	_, err = join(",", []string{"a", "b"})
+	require.NoError(t, err)
+	_, err = join(";", []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	format := MemoizeFn3(memoizer, func(n int, base int, prefix string) (string, error) {
+		if base < 2 {
+			return "", errors.New("invalid base")
+		}
+		return prefix + strconv.FormatInt(int64(n), base), nil
+	})
+	value, err = format(255, 16, "0x")
+	require.NoError(t, err)
+	assert.Equal(t, "0xff", value)
+	_, err = format(255, 1, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package memoizer
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForgetStartsFreshComputation(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fn := func() (int, error) {
+		n := calls.Add(1)
+		if n == 1 {
+			close(started)
+			<-release
+		}
+		return int(n), nil
+	}
+
+	first := m.MemoizeDoChan("key", fn)
+	<-started
+	m.Forget("key")
+
+	value, err := m.Memoize("key", fn)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value, "a caller after Forget should not join the forgotten computation")
+
+	close(release)
+	select {
+	case r := <-first:
+		require.NoError(t, r.Err)
+		assert.Equal(t, 1, r.Value)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the forgotten computation")
+	}
+	assert.EqualValues(t, 2, calls.Load())
+}
@@ -1,6 +1,6 @@
 module github.com/KevinWang15/memoizer
 
-go 1.20
+go 1.21
 
 require (
 	github.com/patrickmn/go-cache v2.1.0+incompatible
@@ -10,6 +10,9 @@ require (
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rogpeppe/go-internal v1.10.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
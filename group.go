@@ -0,0 +1,86 @@
+package memoizer
+
+import (
+	"context"
+	"time"
+)
+
+// Group is a namespace within a Memoizer: it prefixes every key passed to it with the group's
+// name, so that one Memoizer, with its store, limits and statistics, can serve several
+// logical caches whose keys cannot collide and which can be flushed independently. The
+// entries of a group are ordinary entries of the Memoizer, stored under the prefixed keys.
+type Group[T any] struct {
+	m      *Memoizer[T]
+	prefix string
+}
+
+// Group returns the group of the Memoizer called name, whose keys are prefixed with name and
+// a colon. Groups with the same name share their entries.
+//
+// Example usage:
+//
+//	users := cache.Group("users")
+//	user, err := users.Memoize("42", fetchUser) // cached under "users:42"
+//	users.Flush()                               // other groups are unaffected
+func (m *Memoizer[T]) Group(name string) *Group[T] {
+	return &Group[T]{m: m, prefix: name + ":"}
+}
+
+// Group returns a group nested in g, whose keys are prefixed with the prefix of g, name and a
+// colon. Flushing g also flushes it.
+func (g *Group[T]) Group(name string) *Group[T] {
+	return &Group[T]{m: g.m, prefix: g.prefix + name + ":"}
+}
+
+// Prefix returns the prefix of the keys of the group.
+func (g *Group[T]) Prefix() string {
+	return g.prefix
+}
+
+// Memoize is Memoizer.Memoize for key in the group.
+func (g *Group[T]) Memoize(key string, fn func() (T, error), options ...Option) (T, error) {
+	return g.m.Memoize(g.prefix+key, fn, options...)
+}
+
+// MemoizeWithInfo is Memoizer.MemoizeWithInfo for key in the group.
+func (g *Group[T]) MemoizeWithInfo(key string, fn func() (T, error), options ...Option) (Entry[T], error) {
+	return g.m.MemoizeWithInfo(g.prefix+key, fn, options...)
+}
+
+// MemoizeContext is Memoizer.MemoizeContext for key in the group.
+func (g *Group[T]) MemoizeContext(ctx context.Context, key string, fn func(ctx context.Context) (T, error), options ...Option) (T, error) {
+	return g.m.MemoizeContext(ctx, g.prefix+key, fn, options...)
+}
+
+// MemoizeDoChan is Memoizer.MemoizeDoChan for key in the group.
+func (g *Group[T]) MemoizeDoChan(key string, fn func() (T, error), options ...Option) <-chan Result[T] {
+	return g.m.MemoizeDoChan(g.prefix+key, fn, options...)
+}
+
+// Set is Memoizer.Set for key in the group.
+func (g *Group[T]) Set(key string, value T, ttl time.Duration) {
+	g.m.Set(g.prefix+key, value, ttl)
+}
+
+// Peek is Memoizer.Peek for key in the group.
+func (g *Group[T]) Peek(key string) (T, bool) {
+	return g.m.Peek(g.prefix + key)
+}
+
+// Forget is Memoizer.Forget for key in the group.
+func (g *Group[T]) Forget(key string) {
+	g.m.Forget(g.prefix + key)
+}
+
+// Delete is Memoizer.Delete for key in the group.
+func (g *Group[T]) Delete(key string) {
+	g.m.Delete(g.prefix + key)
+}
+
+// Flush removes every entry of the group, including those of nested groups, and leaves the
+// rest of the Memoizer untouched. It is DeletePrefix with the prefix of the group, so with
+// WithKeyHasher it only finds the keys whose transformation keeps the prefix, as HashLongKeys
+// does for short enough keys.
+func (g *Group[T]) Flush() {
+	g.m.DeletePrefix(g.prefix)
+}
@@ -0,0 +1,44 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	users, orders := memoizer.Group("users"), memoizer.Group("orders")
+	admins := users.Group("admins")
+	assert.Equal(t, "users:admins:", admins.Prefix())
+
+	for _, group := range []*Group[string]{users, orders, admins} {
+		group := group
+		value, err := group.Memoize("42", func() (string, error) { return group.Prefix() + "value", nil })
+		require.NoError(t, err)
+		assert.Equal(t, group.Prefix()+"value", value)
+	}
+	value, ok := memoizer.Peek("users:42")
+	assert.True(t, ok)
+	assert.Equal(t, "users:value", value)
+	value, ok = orders.Peek("42")
+	assert.True(t, ok)
+	assert.Equal(t, "orders:value", value)
+
+	users.Set("43", "set", time.Minute)
+	users.Flush()
+	for _, key := range []string{"42", "43"} {
+		_, ok = users.Peek(key)
+		assert.False(t, ok)
+	}
+	_, ok = admins.Peek("42")
+	assert.False(t, ok, "nested groups are flushed with their parent")
+	_, ok = orders.Peek("42")
+	assert.True(t, ok, "other groups are unaffected")
+
+	orders.Delete("42")
+	_, ok = memoizer.Peek("orders:42")
+	assert.False(t, ok)
+}
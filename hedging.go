@@ -0,0 +1,46 @@
+package memoizer
+
+import "time"
+
+// hedged returns fn run with hedging: if the first attempt has not finished after delay, a
+// second attempt starts, and the first attempt to succeed wins. If both fail, the error or
+// panic of the attempt that finished last is returned.
+func hedged[T any](fn func() (T, error), delay time.Duration) func() (T, error) {
+	return func() (T, error) {
+		type outcome struct {
+			res T
+			err error
+			p   *recoveredPanic
+		}
+		// Buffered for both attempts, so that the losing one does not leak.
+		done := make(chan outcome, 2)
+		attempt := func() {
+			res, err, p := callRecovering(fn)
+			done <- outcome{res: res, err: err, p: p}
+		}
+		go attempt()
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		pending := 1
+		for {
+			select {
+			case o := <-done:
+				pending--
+				if o.p == nil && o.err == nil {
+					return o.res, nil
+				}
+				if pending > 0 {
+					continue
+				}
+				if o.p != nil {
+					panic(o.p)
+				}
+				return o.res, o.err
+			case <-timer.C:
+				pending++
+				go attempt()
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package memoizer
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedgingFirstSuccessWins(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		n := calls.Add(1)
+		if n == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		return int(n), nil
+	}
+
+	start := time.Now()
+	value, err := m.Memoize("key", fn, WithHedging(10*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 2, value, "the hedged call should win")
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestHedgingSkipsFastCalls(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	failure := errors.New("failure")
+
+	_, err := m.Memoize("key", func() (int, error) {
+		calls.Add(1)
+		return 0, failure
+	}, WithHedging(50*time.Millisecond))
+	assert.ErrorIs(t, err, failure)
+	time.Sleep(60 * time.Millisecond)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestHedgingWaitsForOtherAttemptOnFailure(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		if calls.Add(1) == 1 {
+			time.Sleep(30 * time.Millisecond)
+			return 0, errors.New("slow failure")
+		}
+		time.Sleep(40 * time.Millisecond)
+		return 42, nil
+	}
+
+	value, err := m.Memoize("key", fn, WithHedging(10*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
+
+func TestHedgingReturnsLastFailure(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		n := calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		if n == 1 {
+			return 0, errors.New("first")
+		}
+		panic("second")
+	}
+
+	assert.PanicsWithValue(t, "second", func() {
+		_, _ = m.Memoize("key", fn, WithHedging(10*time.Millisecond))
+	})
+}
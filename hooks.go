@@ -0,0 +1,22 @@
+package memoizer
+
+import "time"
+
+// Hooks are called on cache lifecycle events of a Memoizer, as installed with WithHooks. Any
+// of them may be nil. They run synchronously on the goroutine that triggered the event, so
+// they should return quickly and must not call back into the Memoizer's invalidation methods.
+type Hooks[T any] struct {
+	// OnHit is called when a lookup of key is served from the cache with value.
+	OnHit func(key string, value T)
+	// OnMiss is called when a lookup of key is not served from the cache.
+	OnMiss func(key string)
+	// OnEvict is called when the store removes the entry of key through expiry or to make room
+	// for other entries, if the store is an EvictionNotifier. It is not called for entries
+	// removed with Delete and its variants. value is the zero value if the store does not hold
+	// values of type T.
+	OnEvict func(key string, value T)
+	// OnError is called when a computation of key returns err after running for latency. It is
+	// also called, with an error wrapping ErrTagLimit and a zero latency, when tags of key are
+	// dropped because of WithMaxTagsPerEntry or WithMaxDistinctTags.
+	OnError func(key string, err error, latency time.Duration)
+}
@@ -0,0 +1,53 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoizerWithHooks(t *testing.T) {
+	var events []string
+	memoizer := NewMemoizerWithOptions[int](WithMaxEntries(1), WithHooks(Hooks[int]{
+		OnHit: func(key string, value int) {
+			events = append(events, "hit "+key)
+			assert.Equal(t, 1, value)
+		},
+		OnMiss: func(key string) {
+			events = append(events, "miss "+key)
+		},
+		OnEvict: func(key string, value int) {
+			events = append(events, "evict "+key)
+			assert.Equal(t, 1, value)
+		},
+		OnError: func(key string, err error, latency time.Duration) {
+			events = append(events, "error "+key+": "+err.Error())
+			assert.Positive(t, latency)
+		},
+	}))
+
+	_, _ = memoizer.Memoize("a", func() (int, error) { return 1, nil })
+	_, _ = memoizer.Memoize("a", func() (int, error) { return 1, nil })
+	_, _ = memoizer.Memoize("b", func() (int, error) { return 2, nil })
+	_, _ = memoizer.Memoize("c", func() (int, error) {
+		time.Sleep(time.Millisecond)
+		return 0, errors.New("failed")
+	})
+	memoizer.Delete("b")
+
+	assert.Equal(t, []string{"miss a", "hit a", "miss b", "evict a", "miss c", "error c: failed"}, events)
+	assert.True(t, memoizer.Config().Hooks)
+}
+
+func TestMemoizerWithHooksOfAnotherType(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithHooks(Hooks[string]{
+		OnMiss: func(string) {
+			t.Fatal("hooks of another type should be ignored")
+		},
+	}))
+
+	_, _ = memoizer.Memoize("a", func() (int, error) { return 1, nil })
+	assert.False(t, memoizer.Config().Hooks)
+}
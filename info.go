@@ -0,0 +1,75 @@
+package memoizer
+
+import "time"
+
+// Entry is a memoized value along with metadata about where it came from, as returned by
+// MemoizeWithInfo.
+type Entry[T any] struct {
+	Value T
+	// CachedAt is the time the value was computed and stored, or the zero time if it was not
+	// stored, such as after an error.
+	CachedAt time.Time
+	// ExpiresAt is the time the cached value expires, or the zero time if it never expires or
+	// the store cannot report it. With WithStaleWhileRevalidate or WithServeStaleOnError, it is
+	// the time the value goes stale.
+	ExpiresAt time.Time
+	// FromCache reports whether the value was served from the cache without a computation.
+	FromCache bool
+	// Shared reports whether the computation of the value was shared with other callers,
+	// including for the caller that ran it.
+	Shared bool
+	// Source tells whether the caller ran the computation itself, waited for a computation
+	// another caller started, or was served from the cache.
+	Source Source
+}
+
+// Source describes where the value returned to a caller came from.
+type Source int
+
+const (
+	// SourceNone means no value was produced for the caller, such as when a call failed fast
+	// without a computation.
+	SourceNone Source = iota
+	// SourceCache means the value, or the cached error, was served from the cache.
+	SourceCache
+	// SourceComputed means the caller ran the computation itself.
+	SourceComputed
+	// SourceShared means the caller waited for a computation that another caller started.
+	SourceShared
+)
+
+// String returns the name of s.
+func (s Source) String() string {
+	switch s {
+	case SourceCache:
+		return "cache"
+	case SourceComputed:
+		return "computed"
+	case SourceShared:
+		return "shared"
+	default:
+		return "none"
+	}
+}
+
+// MemoizeWithInfo is like Memoize, but also returns metadata about the value, such as its age,
+// so that callers can surface it in APIs or decide whether to trust a cached result.
+func (m *Memoizer[T]) MemoizeWithInfo(key string, fn func() (T, error), options ...Option) (Entry[T], error) {
+	key = m.hashKey(key)
+	value, source, shared, err := m.memoize(key, fn, m.resolve(options))
+	entry := Entry[T]{Value: value, FromCache: source == SourceCache, Shared: shared, Source: source}
+	if err != nil {
+		return entry, err
+	}
+	if _, ok := m.retained(key); ok {
+		stat := m.access.get(key)
+		entry.CachedAt = stat.storedAt
+		entry.ExpiresAt = stat.staleAt
+		if entry.ExpiresAt.IsZero() {
+			if store, ok := m.store.(InspectableStore); ok {
+				_, entry.ExpiresAt, _ = store.GetWithExpiration(key)
+			}
+		}
+	}
+	return entry, nil
+}
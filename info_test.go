@@ -0,0 +1,85 @@
+package memoizer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerMemoizeWithInfo(t *testing.T) {
+	memoizer := NewMemoizerWithCacheExpiration[int](time.Minute)
+	fn := func() (int, error) { return 42, nil }
+
+	before := time.Now()
+	entry, err := memoizer.MemoizeWithInfo("key", fn)
+	require.NoError(t, err)
+	assert.Equal(t, 42, entry.Value)
+	assert.False(t, entry.FromCache)
+	assert.False(t, entry.Shared)
+	assert.Equal(t, SourceComputed, entry.Source)
+	assert.WithinDuration(t, before, entry.CachedAt, time.Second)
+	assert.WithinDuration(t, before.Add(time.Minute), entry.ExpiresAt, time.Second)
+
+	cached, err := memoizer.MemoizeWithInfo("key", fn)
+	require.NoError(t, err)
+	assert.True(t, cached.FromCache)
+	assert.Equal(t, SourceCache, cached.Source)
+	assert.Equal(t, "cache", cached.Source.String())
+	assert.Equal(t, entry.CachedAt, cached.CachedAt)
+	assert.Equal(t, entry.ExpiresAt, cached.ExpiresAt)
+}
+
+func TestMemoizerMemoizeWithInfoNoExpiration(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	entry, err := memoizer.MemoizeWithInfo("key", func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	assert.False(t, entry.CachedAt.IsZero())
+	assert.True(t, entry.ExpiresAt.IsZero())
+}
+
+func TestMemoizerMemoizeWithInfoError(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	entry, err := memoizer.MemoizeWithInfo("key", func() (int, error) { return 0, errors.New("failed") })
+	require.Error(t, err)
+	assert.True(t, entry.CachedAt.IsZero())
+	assert.False(t, entry.FromCache)
+}
+
+func TestMemoizerMemoizeWithInfoShared(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var once sync.Once
+	fn := func() (int, error) {
+		once.Do(func() { close(started) })
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	entries := make([]Entry[int], 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		entries[0], _ = memoizer.MemoizeWithInfo("key", fn)
+	}()
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		entries[1], _ = memoizer.MemoizeWithInfo("key", fn)
+	}()
+	// Give the second caller time to join the computation.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.True(t, entries[0].Shared)
+	assert.True(t, entries[1].Shared)
+	assert.Equal(t, SourceComputed, entries[0].Source)
+	assert.Equal(t, SourceShared, entries[1].Source)
+}
@@ -0,0 +1,164 @@
+package memoizer
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// EntryInfo describes a single cached entry, as reported by Inventory.
+type EntryInfo struct {
+	Key string
+	// RemainingTTL is the time left before the entry expires, or NoExpiration
+	// if the entry never expires.
+	RemainingTTL time.Duration
+	// Hits is the number of times the entry was served from the cache since it was stored.
+	Hits uint64
+	// LastAccess is the time the entry was last stored or served from the cache.
+	LastAccess time.Time
+	// Tags are the tags the entry was stored with, sorted.
+	Tags []string
+}
+
+// accessStat holds per-entry access counters and freshness metadata.
+type accessStat struct {
+	hits       uint64
+	lastAccess time.Time
+	storedAt   time.Time
+	// computeTime is how long the cached value took to compute, if it was computed by the
+	// Memoizer.
+	computeTime time.Duration
+	// checksum is the checksum of the cached value, recorded if hasChecksum is set.
+	checksum    uint64
+	hasChecksum bool
+	freshness
+}
+
+// freshness records when an entry that is kept in the store beyond its expiration goes stale
+// and when it stops being served. Zero times mean the entry is fresh for as long as the store
+// holds it.
+type freshness struct {
+	// staleAt is the time after which the entry is served stale while it is revalidated.
+	staleAt time.Time
+	// expiresAt is the time after which the entry is no longer served, although the store may
+	// retain it as a fallback for failed recomputations.
+	expiresAt time.Time
+	// dueAt is the time the entry's expiration elapses, before any stale window. It is zero if
+	// the entry never expires.
+	dueAt time.Time
+}
+
+// accessTracker records access statistics for cached entries.
+type accessTracker struct {
+	mu    sync.Mutex
+	stats map[string]accessStat
+}
+
+// stored resets the statistics of key after a new value has been cached for it.
+// The entry's value was originally computed at storedAt.
+func (a *accessTracker) stored(key string, storedAt time.Time, f freshness) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stats == nil {
+		a.stats = make(map[string]accessStat)
+	}
+	a.stats[key] = accessStat{lastAccess: time.Now(), storedAt: storedAt, freshness: f}
+}
+
+// refreshed replaces the freshness of key after its expiration was restarted.
+func (a *accessTracker) refreshed(key string, f freshness) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if stat, ok := a.stats[key]; ok {
+		stat.freshness = f
+		a.stats[key] = stat
+	}
+}
+
+// hit records that key was served from the cache.
+func (a *accessTracker) hit(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stats == nil {
+		a.stats = make(map[string]accessStat)
+	}
+	stat := a.stats[key]
+	stat.hits++
+	stat.lastAccess = time.Now()
+	a.stats[key] = stat
+}
+
+// remove drops the statistics of key once it has left the cache.
+func (a *accessTracker) remove(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.stats, key)
+}
+
+// reset drops the statistics of every key, once the cache has been flushed.
+func (a *accessTracker) reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stats = nil
+}
+
+// keys returns the keys that have statistics recorded.
+func (a *accessTracker) keys() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	keys := make([]string, 0, len(a.stats))
+	for key := range a.stats {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// freshness returns the freshness recorded for key.
+func (a *accessTracker) freshness(key string) freshness {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stats[key].freshness
+}
+
+// get returns the statistics recorded for key.
+func (a *accessTracker) get(key string) accessStat {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stats[key]
+}
+
+// Inventory returns a point-in-time description of every unexpired entry, ordered by key,
+// including its remaining TTL and access statistics. It is intended for offline analysis
+// such as capacity modeling. It requires a store that implements InspectableStore, and is
+// empty otherwise.
+func (m *Memoizer[T]) Inventory() []EntryInfo {
+	now := time.Now()
+	items := m.entries()
+	inventory := make([]EntryInfo, 0, len(items))
+	for key, item := range items {
+		stat := m.access.get(key)
+		// The store retains entries beyond their expiration with WithServeStaleOnError; such
+		// entries are no longer served, and are left out.
+		expiresAt := item.ExpiresAt
+		if !stat.freshness.expiresAt.IsZero() {
+			expiresAt = stat.freshness.expiresAt
+		}
+		remaining := NoExpiration
+		if !expiresAt.IsZero() {
+			if remaining = expiresAt.Sub(now); remaining < 0 {
+				continue
+			}
+		}
+		inventory = append(inventory, EntryInfo{
+			Key:          key,
+			RemainingTTL: remaining,
+			Hits:         stat.hits,
+			LastAccess:   stat.lastAccess,
+			Tags:         m.tags.tags(key),
+		})
+	}
+	sort.Slice(inventory, func(i, j int) bool {
+		return inventory[i].Key < inventory[j].Key
+	})
+	return inventory
+}
@@ -0,0 +1,64 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	fn := func() (int, error) {
+		return 42, nil
+	}
+
+	_, err := memoizer.Memoize("forever", fn)
+	require.NoError(t, err)
+	_, err = memoizer.Memoize("short", fn, WithExpiration(func(interface{}) time.Duration {
+		return time.Minute
+	}))
+	require.NoError(t, err)
+
+	before := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err = memoizer.Memoize("short", fn)
+		require.NoError(t, err)
+	}
+
+	inventory := memoizer.Inventory()
+	require.Len(t, inventory, 2)
+
+	assert.Equal(t, "forever", inventory[0].Key)
+	assert.Equal(t, NoExpiration, inventory[0].RemainingTTL)
+	assert.Equal(t, uint64(0), inventory[0].Hits)
+
+	assert.Equal(t, "short", inventory[1].Key)
+	assert.InDelta(t, float64(time.Minute), float64(inventory[1].RemainingTTL), float64(time.Second))
+	assert.Equal(t, uint64(3), inventory[1].Hits)
+	assert.False(t, inventory[1].LastAccess.Before(before), "last access should reflect the most recent hit")
+
+	// Recomputing an entry resets its statistics.
+	memoizer.store.Delete("short")
+	_, err = memoizer.Memoize("short", fn)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), memoizer.Inventory()[1].Hits)
+}
+
+func TestInventoryWithServeStaleOnError(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	fn := func() (int, error) { return 42, nil }
+	_, err := memoizer.Memoize("retained", fn, WithTTL(time.Minute), WithServeStaleOnError(time.Hour))
+	require.NoError(t, err)
+	_, err = memoizer.Memoize("expired", fn, WithTTL(10*time.Millisecond), WithServeStaleOnError(time.Hour))
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	// The store retains both entries for another hour, but they are only served until their
+	// TTL elapses.
+	inventory := memoizer.Inventory()
+	require.Len(t, inventory, 1)
+	assert.Equal(t, "retained", inventory[0].Key)
+	assert.InDelta(t, float64(time.Minute), float64(inventory[0].RemainingTTL), float64(time.Second))
+}
@@ -0,0 +1,46 @@
+package memoizer
+
+// Iterator is a cursor over the entries of a Memoizer, created by Iterator.
+//
+// An Iterator captures the set of keys present when it is created, then reads each value
+// only when Next reaches it. It never observes keys added afterwards, skips keys that have
+// been removed or have expired in the meantime, and returns the current value of keys that
+// were overwritten. Like Peek, it skips expired entries retained only as a fallback for
+// failed recomputations. Unlike Snapshot, it does not retain values, so memory stays bounded
+// by the key set while scanning a large cache, although the default store still briefly
+// copies its item map when the Iterator is created. Iterating requires a store that
+// implements InspectableStore; other stores yield no entries.
+//
+// An Iterator is not safe for concurrent use, but the Memoizer may be modified freely while
+// it is being iterated.
+type Iterator[T any] struct {
+	m    *Memoizer[T]
+	keys []string
+}
+
+// Iterator returns a cursor over the Memoizer's current entries, in no particular order.
+func (m *Memoizer[T]) Iterator() *Iterator[T] {
+	var keys []string
+	if store, ok := m.store.(InspectableStore); ok {
+		store.Range(func(key string, _ StoreEntry) bool {
+			keys = append(keys, key)
+			return true
+		})
+	}
+	return &Iterator[T]{m: m, keys: keys}
+}
+
+// Next returns the next entry that is still cached. ok is false once the iterator is exhausted.
+func (it *Iterator[T]) Next() (key string, value T, ok bool) {
+	for len(it.keys) > 0 {
+		key = it.keys[0]
+		it.keys[0] = ""
+		it.keys = it.keys[1:]
+
+		if value, ok = it.m.retained(key); ok && it.m.servable(key) {
+			return key, value, true
+		}
+	}
+	var zero T
+	return "", zero, false
+}
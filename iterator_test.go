@@ -0,0 +1,80 @@
+package memoizer
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterator(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	values := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		values["key"+strconv.Itoa(i)] = i
+	}
+	memoizer.SetAll(values)
+
+	it := memoizer.Iterator()
+
+	// Concurrent writers add new keys while iterating.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			memoizer.SetAll(map[string]int{"new" + strconv.Itoa(i): i})
+		}
+	}()
+
+	seen := make(map[string]int)
+	for {
+		key, value, ok := it.Next()
+		if !ok {
+			break
+		}
+		_, duplicate := seen[key]
+		assert.False(t, duplicate, "each entry should be visited once")
+		seen[key] = value
+	}
+	wg.Wait()
+
+	assert.Equal(t, values, seen, "iteration should cover the entries present at the start")
+
+	_, _, ok := it.Next()
+	assert.False(t, ok)
+}
+
+func TestIteratorSkipsRemovedEntries(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	memoizer.SetAll(map[string]int{"a": 1, "b": 2})
+
+	it := memoizer.Iterator()
+	memoizer.store.Delete("a")
+
+	key, value, ok := it.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, 2, value)
+
+	_, _, ok = it.Next()
+	assert.False(t, ok)
+}
+
+func TestIteratorSkipsRetainedStaleEntries(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	memoizer.Set("fresh", 1, NoExpiration)
+	_, err := memoizer.Memoize("stale", func() (int, error) { return 2, nil }, WithTTL(10*time.Millisecond), WithServeStaleOnError(time.Minute))
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	it := memoizer.Iterator()
+	key, _, ok := it.Next()
+	assert.True(t, ok)
+	assert.Equal(t, "fresh", key)
+	_, _, ok = it.Next()
+	assert.False(t, ok, "an entry retained only as an error fallback should not be yielded")
+}
@@ -0,0 +1,11 @@
+package memoizer
+
+import (
+	"math/rand"
+	"time"
+)
+
+// jitter returns d randomized uniformly within ±fraction of it.
+func jitter(d time.Duration, fraction float64) time.Duration {
+	return time.Duration(float64(d) * (1 + fraction*(2*rand.Float64()-1)))
+}
@@ -0,0 +1,47 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLJitterSpreadsExpirations(t *testing.T) {
+	m := NewMemoizerWithCacheExpiration[int](time.Hour)
+	options := []Option{WithTTLJitter(0.1)}
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		expiration := m.expiration(0, resolveOptions[int](options))
+		assert.GreaterOrEqual(t, expiration, 54*time.Minute)
+		assert.LessOrEqual(t, expiration, 66*time.Minute)
+		seen[expiration] = true
+	}
+	assert.Greater(t, len(seen), 1, "expirations should be randomized")
+}
+
+func TestTTLJitterRespectsBoundsAndNoExpiration(t *testing.T) {
+	m := NewMemoizer[int]()
+	assert.Equal(t, NoExpiration, m.expiration(0, resolveOptions[int]([]Option{WithTTLJitter(0.5)})))
+
+	bounded := resolveOptions[int]([]Option{
+		WithExpiration(func(interface{}) time.Duration { return time.Minute }),
+		WithTTLJitter(0.5),
+		WithMaxTTL(time.Minute),
+	})
+	for i := 0; i < 20; i++ {
+		assert.LessOrEqual(t, m.expiration(0, bounded), time.Minute)
+	}
+}
+
+func TestTTLJitterAppliesToCachedEntries(t *testing.T) {
+	m := NewMemoizerWithCacheExpiration[int](time.Hour)
+	_, err := m.Memoize("key", func() (int, error) { return 1, nil }, WithTTLJitter(0.1))
+	require.NoError(t, err)
+
+	inventory := m.Inventory()
+	require.Len(t, inventory, 1)
+	assert.InDelta(t, float64(time.Hour), float64(inventory[0].RemainingTTL), float64(6*time.Minute)+float64(time.Second))
+}
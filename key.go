@@ -0,0 +1,156 @@
+package memoizer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+	"unsafe"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// KeyOf derives a cache key from v, such as the parameters of a query, so that equal values
+// map to the same key in every process. The key is the hex-encoded SHA-256 hash of the type
+// and contents of v:
+//
+//   - Pointers are followed, so pointers to equal values have the same key; a nil pointer
+//     differs from a pointer to a zero value.
+//   - Unexported fields count like exported ones.
+//   - Maps have the same key whatever their iteration order.
+//   - A time.Time counts as its instant, so the same instant in different locations has the
+//     same key.
+//   - Channels and functions count by identity, so their keys differ between processes.
+//
+// Values of different types, such as int32(1) and int64(1), have different keys.
+//
+// Example usage:
+//
+//	key := "search:" + memoizer.KeyOf(SearchQuery{Text: "go", Tags: []string{"lang"}})
+func KeyOf(v interface{}) string {
+	h := sha256.New()
+	value := reflect.ValueOf(v)
+	if value.IsValid() {
+		_, _ = h.Write([]byte(value.Type().String()))
+		// An addressable copy lets timeOf read time.Time values from unexported fields.
+		root := reflect.New(value.Type()).Elem()
+		root.Set(value)
+		value = root
+	}
+	writeKey(h, value, make(map[uintptr]int))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// timeOf returns the time.Time held by v, if v holds one that can be read.
+func timeOf(v reflect.Value) (time.Time, bool) {
+	switch {
+	case v.Type() != timeType:
+		return time.Time{}, false
+	case v.CanInterface():
+		return v.Interface().(time.Time), true
+	case v.CanAddr():
+		// v was reached through an unexported field.
+		return *(*time.Time)(unsafe.Pointer(v.UnsafeAddr())), true
+	default:
+		// v is an unexported map entry, which is written like any other struct.
+		return time.Time{}, false
+	}
+}
+
+// writeKey writes the contents of v to h. visited maps the pointers being followed to their
+// depth, so that a cycle is written as a reference to where it starts.
+func writeKey(h hash.Hash, v reflect.Value, visited map[uintptr]int) {
+	var buf [8]byte
+	writeUint := func(n uint64) {
+		binary.LittleEndian.PutUint64(buf[:], n)
+		_, _ = h.Write(buf[:])
+	}
+
+	if !v.IsValid() {
+		writeUint(0)
+		return
+	}
+	writeUint(uint64(v.Kind()))
+	if t, ok := timeOf(v); ok {
+		// The wall clock, monotonic reading and location of a time.Time do not change its instant.
+		writeUint(uint64(t.Unix()))
+		writeUint(uint64(t.Nanosecond()))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(math.Float64bits(real(v.Complex())))
+		writeUint(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		_, _ = h.Write([]byte(v.String()))
+	case reflect.Pointer:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		if depth, ok := visited[v.Pointer()]; ok {
+			writeUint(2)
+			writeUint(uint64(depth))
+			return
+		}
+		writeUint(1)
+		visited[v.Pointer()] = len(visited)
+		writeKey(h, v.Elem(), visited)
+		delete(visited, v.Pointer())
+	case reflect.Interface:
+		if !v.IsNil() {
+			_, _ = h.Write([]byte(v.Elem().Type().String()))
+		}
+		writeKey(h, v.Elem(), visited)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			writeUint(math.MaxUint64)
+			return
+		}
+		writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			writeKey(h, v.Index(i), visited)
+		}
+	case reflect.Map:
+		// Map iteration order is random, so entries are hashed separately and written in the
+		// order of their hashes.
+		writeUint(uint64(v.Len()))
+		entries := make([][]byte, 0, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			entry := sha256.New()
+			writeKey(entry, iter.Key(), visited)
+			writeKey(entry, iter.Value(), visited)
+			entries = append(entries, entry.Sum(nil))
+		}
+		sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i], entries[j]) < 0 })
+		for _, entry := range entries {
+			_, _ = h.Write(entry)
+		}
+	case reflect.Struct:
+		writeUint(uint64(v.NumField()))
+		for i := 0; i < v.NumField(); i++ {
+			writeKey(h, v.Field(i), visited)
+		}
+	default:
+		// Channels, functions and unsafe pointers count by identity.
+		writeUint(uint64(v.Pointer()))
+	}
+}
@@ -0,0 +1,78 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keyQuery struct {
+	Text   string
+	Tags   []string
+	Filter map[string]int
+	Since  time.Time
+	limit  *int
+}
+
+type keyNode struct {
+	Name string
+	Next *keyNode
+}
+
+func TestKeyOf(t *testing.T) {
+	limit, otherLimit := 10, 10
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	query := keyQuery{Text: "go", Tags: []string{"lang"}, Filter: map[string]int{"a": 1, "b": 2, "c": 3}, Since: since, limit: &limit}
+	same := keyQuery{Text: "go", Tags: []string{"lang"}, Filter: map[string]int{"c": 3, "b": 2, "a": 1}, Since: since.In(time.FixedZone("X", 3600)), limit: &otherLimit}
+
+	key := KeyOf(query)
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, KeyOf(same))
+	assert.Equal(t, KeyOf(&query), KeyOf(&same))
+	now := time.Now()
+	assert.Equal(t, KeyOf(now), KeyOf(now.Round(0).UTC()), "times differing in location and monotonic reading")
+
+	otherLimit = 11
+	assert.NotEqual(t, key, KeyOf(same), "unexported fields count")
+	assert.NotEqual(t, KeyOf(int32(1)), KeyOf(int64(1)))
+	assert.NotEqual(t, KeyOf([]string{"ab", "c"}), KeyOf([]string{"a", "bc"}))
+	assert.NotEqual(t, KeyOf((*int)(nil)), KeyOf(new(int)))
+	assert.NotEqual(t, KeyOf([]int(nil)), KeyOf([]int{}))
+	assert.Equal(t, KeyOf(nil), KeyOf(nil))
+
+	// Cycles terminate and are keyed by their structure.
+	a := &keyNode{Name: "a"}
+	a.Next = a
+	b := &keyNode{Name: "a"}
+	b.Next = b
+	assert.Equal(t, KeyOf(a), KeyOf(b))
+}
+
+func TestMemoizeFnWithKeyFunc(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	calls := 0
+	count := func(query keyQuery) (int, error) {
+		calls++
+		return *query.limit, nil
+	}
+	first, second := 2, 2
+
+	byValue := MemoizeFn1(memoizer, count, WithKeyFunc(KeyOf))
+	for _, limit := range []*int{&first, &second} {
+		value, err := byValue(keyQuery{limit: limit})
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+	}
+	assert.Equal(t, 1, calls)
+
+	// By default, nested pointers are compared by address.
+	calls = 0
+	byAddress := MemoizeFn1(NewMemoizer[int](), count)
+	for _, limit := range []*int{&first, &second} {
+		_, err := byAddress(keyQuery{limit: limit})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
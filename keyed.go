@@ -0,0 +1,50 @@
+package memoizer
+
+import (
+	"context"
+	"fmt"
+)
+
+// KeyedMemoizer is a Memoizer whose keys are of any comparable type K, such as ints, structs
+// or arrays, rather than strings. Keys are converted to cache keys by their type and Go-syntax
+// representation, so callers do not need to serialize them by hand.
+//
+// Two keys map to the same cache entry when they are equal, with two exceptions inherited from
+// their formatting: floating-point zero and negative zero are cached separately, and all NaN
+// values share an entry. Pointer keys are cached by address, as with map keys.
+type KeyedMemoizer[K comparable, V any] struct {
+	m *Memoizer[V]
+}
+
+// NewKeyedMemoizer creates and returns a new instance of a KeyedMemoizer, configured by the
+// same constructor options as New.
+func NewKeyedMemoizer[K comparable, V any](options ...Option) *KeyedMemoizer[K, V] {
+	return &KeyedMemoizer[K, V]{m: New[V](options...)}
+}
+
+// Memoize is like Memoizer.Memoize, with a key of type K.
+func (k *KeyedMemoizer[K, V]) Memoize(key K, fn func() (V, error), options ...Option) (V, error) {
+	return k.m.Memoize(k.cacheKey(key), fn, options...)
+}
+
+// MemoizeContext is like Memoizer.MemoizeContext, with a key of type K.
+func (k *KeyedMemoizer[K, V]) MemoizeContext(ctx context.Context, key K, fn func(ctx context.Context) (V, error), options ...Option) (V, error) {
+	return k.m.MemoizeContext(ctx, k.cacheKey(key), fn, options...)
+}
+
+// Delete is like Memoizer.Delete, with a key of type K.
+func (k *KeyedMemoizer[K, V]) Delete(key K) {
+	k.m.Delete(k.cacheKey(key))
+}
+
+// Memoizer returns the underlying string-keyed Memoizer, for features that are not exposed
+// on KeyedMemoizer. Its keys are the formatted representation of the K keys.
+func (k *KeyedMemoizer[K, V]) Memoizer() *Memoizer[V] {
+	return k.m
+}
+
+// cacheKey converts a key to the string used by the underlying Memoizer. The dynamic type is
+// included so that keys of an interface type K, such as int(1) and int64(1), stay distinct.
+func (k *KeyedMemoizer[K, V]) cacheKey(key K) string {
+	return fmt.Sprintf("%T:%#v", key, key)
+}
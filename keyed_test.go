@@ -0,0 +1,74 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y int
+}
+
+func TestKeyedMemoizer(t *testing.T) {
+	memoizer := NewKeyedMemoizer[point, string]()
+	callCount := 0
+	fn := func(p point) func() (string, error) {
+		return func() (string, error) {
+			callCount++
+			return "computed", nil
+		}
+	}
+
+	for _, p := range []point{{1, 2}, {2, 1}, {1, 2}} {
+		result, err := memoizer.Memoize(p, fn(p))
+		require.NoError(t, err)
+		assert.Equal(t, "computed", result)
+	}
+	assert.Equal(t, 2, callCount, "equal struct keys should share an entry")
+
+	result, err := memoizer.Memoize(point{2, 1}, func() (string, error) {
+		return "", errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "computed", result)
+}
+
+func TestKeyedMemoizerInterfaceKeys(t *testing.T) {
+	memoizer := NewKeyedMemoizer[any, string]()
+
+	a, err := memoizer.Memoize(1, func() (string, error) {
+		return "int", nil
+	})
+	require.NoError(t, err)
+	b, err := memoizer.Memoize(int64(1), func() (string, error) {
+		return "int64", nil
+	})
+	require.NoError(t, err)
+	c, err := memoizer.Memoize("1", func() (string, error) {
+		return "string", nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"int", "int64", "string"}, []string{a, b, c}, "keys of different types should not collide")
+	assert.Equal(t, 3, len(memoizer.Memoizer().entries()))
+}
+
+func TestKeyedMemoizerDelete(t *testing.T) {
+	memoizer := NewKeyedMemoizer[point, int]()
+	calls := 0
+	fn := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	_, err := memoizer.Memoize(point{1, 2}, fn)
+	require.NoError(t, err)
+	memoizer.Delete(point{1, 2})
+
+	result, err := memoizer.Memoize(point{1, 2}, fn)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result)
+}
@@ -0,0 +1,37 @@
+package memoizer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashKey returns the hex-encoded SHA-256 hash of key, for use with WithKeyHasher.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashLongKeys returns a key hasher for WithKeyHasher that keeps keys of at most maxLen bytes
+// as they are and shortens longer ones to maxLen bytes: their beginning followed by the
+// hex-encoded SHA-256 hash of the whole key. Keeping the beginning keeps shortened keys
+// readable and lets DeletePrefix find them by a short prefix. A maxLen below 64, the length
+// of the hash, is raised to 64.
+func HashLongKeys(maxLen int) func(key string) string {
+	if maxLen < sha256.Size*2 {
+		maxLen = sha256.Size * 2
+	}
+	return func(key string) string {
+		if len(key) <= maxLen {
+			return key
+		}
+		return key[:maxLen-sha256.Size*2] + HashKey(key)
+	}
+}
+
+// hashKey returns the key under which the Memoizer stores the entry for key.
+func (m *Memoizer[T]) hashKey(key string) string {
+	if m.keyHasher == nil {
+		return key
+	}
+	return m.keyHasher(key)
+}
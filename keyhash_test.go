@@ -0,0 +1,70 @@
+package memoizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashLongKeys(t *testing.T) {
+	hash := HashLongKeys(100)
+	assert.Equal(t, "short", hash("short"))
+
+	long := "select * from users where " + strings.Repeat("x", 200)
+	hashed := hash(long)
+	assert.Len(t, hashed, 100)
+	assert.True(t, strings.HasPrefix(hashed, "select * from users"))
+	assert.True(t, strings.HasSuffix(hashed, HashKey(long)))
+	assert.NotEqual(t, hashed, hash(long+"y"))
+
+	assert.Len(t, HashLongKeys(10)(long), 64)
+}
+
+func TestWithKeyHasher(t *testing.T) {
+	memoizer := New[int](WithKeyHasher(HashKey))
+	assert.True(t, memoizer.Config().KeyHashing)
+	key := strings.Repeat("k", 1000)
+	calls := 0
+	fn := func() (int, error) {
+		calls++
+		return 42, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		value, err := memoizer.Memoize(key, fn)
+		require.NoError(t, err)
+		assert.Equal(t, 42, value)
+	}
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{HashKey(key)}, keysOf(memoizer.store.(InspectableStore).Entries()))
+
+	value, ok := memoizer.Peek(key)
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+
+	memoizer.Set("other", 7, 0)
+	assert.True(t, memoizer.Rename("other", "renamed"))
+	value, ok = memoizer.Peek("renamed")
+	assert.True(t, ok)
+	assert.Equal(t, 7, value)
+
+	memoizer.Delete(key)
+	_, ok = memoizer.Peek(key)
+	assert.False(t, ok)
+
+	values, err := memoizer.MemoizeMulti("primary", func() (map[string]int, error) {
+		return map[string]int{"a": 1, "b": 2}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, values)
+	values, err = memoizer.MemoizeMulti("primary", func() (map[string]int, error) {
+		return nil, assert.AnError
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, values)
+	value, ok = memoizer.Peek("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+}
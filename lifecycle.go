@@ -0,0 +1,130 @@
+package memoizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned by the calls of a Memoizer shut down with ShutdownAndPersist.
+var ErrClosed = errors.New("memoizer is shut down")
+
+// activity counts the calls of a Memoizer in progress, and the computations they start, for
+// ShutdownAndPersist to wait for. A call is counted from before it checks for shutdown, so
+// that none can slip past the check unnoticed.
+type activity struct {
+	mu      sync.Mutex
+	closed  bool
+	active  int
+	drained chan struct{} // closed once the activity is closed and no longer active
+}
+
+// enter counts a call, unless the activity is closed. The caller must call leave when it
+// returns if enter reports true.
+func (a *activity) enter() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return false
+	}
+	a.active++
+	return true
+}
+
+// join counts a computation, even once the activity is closed, since computations started
+// by calls, such as background refreshes, may outlive them. The caller must call leave when
+// it finishes.
+func (a *activity) join() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.active++
+}
+
+// leave ends a call or a computation counted by enter or join.
+func (a *activity) leave() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.active--
+	a.signal()
+}
+
+// close refuses further calls, and returns a channel closed once no call or computation is
+// in progress.
+func (a *activity) close() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.closed {
+		a.closed = true
+		a.drained = make(chan struct{})
+		a.signal()
+	}
+	return a.drained
+}
+
+// signal closes drained if the activity is closed and inactive. The caller must hold a.mu.
+func (a *activity) signal() {
+	if !a.closed || a.active > 0 {
+		return
+	}
+	select {
+	case <-a.drained:
+		// A computation joined and left after the activity was drained.
+	default:
+		close(a.drained)
+	}
+}
+
+// Close stops the background work of the Memoizer, so that it can be dropped without leaking
+// goroutines, such as at the end of a test or of a short-lived program. It stops the refreshes
+// of WithAutoRefresh, waiting for those in progress to finish, and the purging of expired
+// entries of WithCleanupInterval, and applies the deletions pending with
+// WithCoalescedInvalidation. With WithPersistence, it stops the periodic writes and writes the
+// cache one last time, returning the error of that write.
+//
+// The Memoizer remains usable, without background work. Close does not close the stores,
+// broadcasters and lockers passed to the Memoizer, which may be shared with others. Calling
+// Close again does nothing.
+func (m *Memoizer[T]) Close() error {
+	var err error
+	m.closeOnce.Do(func() {
+		m.autoRefresher.close()
+		if m.coalescer != nil {
+			m.coalescer.close(m.invalidate)
+		}
+		if store, ok := m.store.(*GoCacheStore); ok && m.ownsStore {
+			store.Close()
+		}
+		m.stopPersistence()
+		err = m.Persist()
+	})
+	return err
+}
+
+// ShutdownAndPersist shuts the Memoizer down gracefully, such as when the process receives a
+// termination signal. From then on, Memoize and its variants fail with ErrClosed, without
+// reading the cache. ShutdownAndPersist then waits for the computations in flight to finish
+// and cache their results, until ctx is done, and finally closes the Memoizer as Close does,
+// which writes the cache with WithPersistence, whether or not the wait completed.
+//
+// It returns ctx.Err() if computations were still in flight when ctx was done, joined with
+// the error of the final write, if any. Methods that do not compute, such as Peek, Set and
+// Delete, keep working.
+//
+// Example usage:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if err := cache.ShutdownAndPersist(ctx); err != nil {
+//	    log.Printf("cache shutdown: %v", err)
+//	}
+func (m *Memoizer[T]) ShutdownAndPersist(ctx context.Context) error {
+	var waitErr error
+	select {
+	case <-m.activity.close():
+	case <-ctx.Done():
+		waitErr = ctx.Err()
+	}
+	// Results of WithTimeout that arrive from now on are no longer cached.
+	m.generation.Add(1)
+	return errors.Join(waitErr, m.Close())
+}
@@ -0,0 +1,70 @@
+package memoizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Loader is a DataLoader-style wrapper around a Memoizer that loads values by typed keys.
+// It derives cache keys from its keys, passes the caller's context to the load function,
+// and supports fetching several keys at once. Concurrent requests for the same key share a
+// single load.
+type Loader[K comparable, V any] struct {
+	m     *Memoizer[V]
+	keyOf func(K) string
+	load  func(ctx context.Context, k K) (V, error)
+}
+
+// NewLoader creates and returns a new Loader that caches in m. keyOf maps each key to its
+// cache key and must be injective; load fetches the value of a key on a cache miss.
+func NewLoader[K comparable, V any](m *Memoizer[V], keyOf func(K) string, load func(ctx context.Context, k K) (V, error)) *Loader[K, V] {
+	return &Loader[K, V]{m: m, keyOf: keyOf, load: load}
+}
+
+// Get returns the value for k, loading it on a cache miss.
+func (l *Loader[K, V]) Get(ctx context.Context, k K, options ...Option) (V, error) {
+	return l.m.MemoizeContext(ctx, l.keyOf(k), func(ctx context.Context) (V, error) {
+		return l.load(ctx, k)
+	}, options...)
+}
+
+// GetMany returns the values for ks, loading missing keys concurrently. Duplicate keys are
+// loaded once. If some loads fail, GetMany returns the values that did load, together with
+// the joined errors of the ones that did not.
+func (l *Loader[K, V]) GetMany(ctx context.Context, ks []K, options ...Option) (map[K]V, error) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		values = make(map[K]V, len(ks))
+		errs   []error
+	)
+	seen := make(map[K]struct{}, len(ks))
+	for _, k := range ks {
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+
+		wg.Add(1)
+		go func(k K) {
+			defer wg.Done()
+			value, err := l.Get(ctx, k, options...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			values[k] = value
+		}(k)
+	}
+	wg.Wait()
+	return values, errors.Join(errs...)
+}
+
+// Invalidate removes the cached value for k, so that the next Get loads it again.
+func (l *Loader[K, V]) Invalidate(k K) {
+	l.m.Delete(l.keyOf(k))
+}
@@ -0,0 +1,69 @@
+package memoizer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLoader(loads *int32) *Loader[int, string] {
+	return NewLoader(NewMemoizer[string](), func(id int) string {
+		return "user:" + strconv.Itoa(id)
+	}, func(ctx context.Context, id int) (string, error) {
+		atomic.AddInt32(loads, 1)
+		time.Sleep(10 * time.Millisecond) // Simulate work
+		if id < 0 {
+			return "", errors.New("invalid id")
+		}
+		return "user" + strconv.Itoa(id), nil
+	})
+}
+
+func TestLoaderGet(t *testing.T) {
+	var loads int32
+	loader := newTestLoader(&loads)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := loader.Get(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, "user1", value)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&loads), "concurrent gets should share a single load")
+
+	loader.Invalidate(1)
+	_, err := loader.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&loads), "an invalidated key should be loaded again")
+}
+
+func TestLoaderGetMany(t *testing.T) {
+	var loads int32
+	loader := newTestLoader(&loads)
+	ctx := context.Background()
+
+	_, err := loader.Get(ctx, 1)
+	require.NoError(t, err)
+
+	values, err := loader.GetMany(ctx, []int{1, 2, 3, 2})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "user1", 2: "user2", 3: "user3"}, values)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&loads), "cached and duplicate keys should not be loaded again")
+
+	values, err = loader.GetMany(ctx, []int{3, -1})
+	assert.EqualError(t, err, "invalid id")
+	assert.Equal(t, map[int]string{3: "user3"}, values)
+}
@@ -0,0 +1,56 @@
+package memoizer
+
+import "time"
+
+// Locker provides locks shared by the processes of a fleet, such as memoredis.Locker, so that
+// only one of them computes a missing key at a time, as installed with WithDistributedLock.
+// Implementations must be safe for concurrent use.
+type Locker interface {
+	// TryLock attempts to acquire the lock of key without waiting. The lock is released by
+	// calling unlock, or automatically after ttl, in case its holder dies.
+	TryLock(key string, ttl time.Duration) (unlock func(), acquired bool, err error)
+}
+
+// distributedLock holds the configuration of WithDistributedLock.
+type distributedLock struct {
+	locker       Locker
+	ttl          time.Duration
+	pollInterval time.Duration
+}
+
+// defaultLockPollInterval is how often a process waiting for another's computation checks for
+// its result, unless configured otherwise.
+const defaultLockPollInterval = 50 * time.Millisecond
+
+// awaitLock acquires the distributed lock of key before a computation. It returns the lock's
+// unlock function, or the value to use instead of computing: a value another process stored
+// while holding the lock, or an expired value retained by WithServeStaleOnError while another
+// process recomputes it. If the Locker fails, the computation proceeds without the lock.
+func (m *Memoizer[T]) awaitLock(key string, opts callOptions[T]) (unlock func(), value T, ok bool) {
+	for {
+		unlock, acquired, err := m.lock.locker.TryLock(key, m.lock.ttl)
+		if err != nil {
+			if m.logger != nil {
+				m.logger.Warn("memoizer failed to acquire distributed lock", "key", key, "error", err)
+			}
+			return func() {}, value, false
+		}
+		if acquired {
+			// Another process may have stored the value between this process's miss and now.
+			if value, ok := m.retained(key); ok && m.servable(key) {
+				unlock()
+				return nil, value, true
+			}
+			return unlock, value, false
+		}
+
+		if value, ok := m.retained(key); ok {
+			// Either another process stored the value, or an expired value is retained as a
+			// fallback; serve it rather than wait.
+			if m.servable(key) || opts.staleOnErrorFor > 0 {
+				return nil, value, true
+			}
+		}
+		time.Sleep(m.lock.pollInterval)
+	}
+}
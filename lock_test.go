@@ -0,0 +1,102 @@
+package memoizer
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// localLocker is a Locker shared by Memoizers in the same process, standing in for a fleet.
+type localLocker struct {
+	mu    sync.Mutex
+	held  map[string]bool
+	fails error
+}
+
+func (l *localLocker) TryLock(key string, ttl time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fails != nil {
+		return nil, false, l.fails
+	}
+	if l.held == nil {
+		l.held = make(map[string]bool)
+	}
+	if l.held[key] {
+		return nil, false, nil
+	}
+	l.held[key] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, key)
+	}, true, nil
+}
+
+func TestDistributedLockComputesOnceAcrossMemoizers(t *testing.T) {
+	store := newMapStore()
+	locker := &localLocker{}
+	newInstance := func() *Memoizer[int] {
+		return NewMemoizerWithOptions[int](WithStore(store), &DistributedLockOption{Locker: locker, TTL: time.Minute, PollInterval: time.Millisecond})
+	}
+	instances := []*Memoizer[int]{newInstance(), newInstance(), newInstance()}
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	results := make([]int, len(instances))
+	for i, m := range instances {
+		wg.Add(1)
+		go func(i int, m *Memoizer[int]) {
+			defer wg.Done()
+			value, err := m.Memoize("key", func() (int, error) {
+				calls.Add(1)
+				time.Sleep(20 * time.Millisecond)
+				return 42, nil
+			})
+			require.NoError(t, err)
+			results[i] = value
+		}(i, m)
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 1, calls.Load())
+	assert.Equal(t, []int{42, 42, 42}, results)
+	assert.Empty(t, locker.held)
+	assert.Equal(t, time.Minute, instances[0].Config().DistributedLockTTL)
+}
+
+func TestDistributedLockServesRetainedValueWhileLocked(t *testing.T) {
+	locker := &localLocker{}
+	m := NewMemoizerWithOptions[int](WithStore(newMapStore()), &DistributedLockOption{Locker: locker, TTL: time.Minute, PollInterval: time.Millisecond})
+	fallback := WithServeStaleOnError(time.Hour)
+	expiry := WithExpiration(func(interface{}) time.Duration { return 10 * time.Millisecond })
+
+	_, err := m.Memoize("key", func() (int, error) { return 1, nil }, expiry, fallback)
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	// Another process holds the lock while it recomputes the expired value.
+	unlock, acquired, _ := locker.TryLock("key", time.Minute)
+	require.True(t, acquired)
+	defer unlock()
+
+	value, err := m.Memoize("key", func() (int, error) {
+		t.Fatal("computed while another process holds the lock")
+		return 0, nil
+	}, expiry, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
+
+func TestDistributedLockFailureComputesWithoutLock(t *testing.T) {
+	m := NewMemoizerWithOptions[int](WithDistributedLock(&localLocker{fails: errors.New("unreachable")}, time.Minute))
+
+	value, err := m.Memoize("key", func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
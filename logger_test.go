@@ -0,0 +1,60 @@
+package memoizer
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestMemoizerWithLogger(t *testing.T) {
+	var out syncBuffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+	memoizer := NewMemoizerWithOptions[int](WithLogger(logger), WithMaxEntries(1))
+
+	_, _ = memoizer.Memoize("a", func() (int, error) { return 1, nil })
+	_, _ = memoizer.Memoize("a", func() (int, error) { return 1, nil })
+	_, _ = memoizer.Memoize("b", func() (int, error) { return 2, nil })
+	assert.Panics(t, func() {
+		_, _ = memoizer.Memoize("c", func() (int, error) { panic("boom") })
+	})
+
+	assert.Equal(t, []string{
+		`level=DEBUG msg="memoizer cache miss" key=a`,
+		`level=DEBUG msg="memoizer cache hit" key=a`,
+		`level=DEBUG msg="memoizer cache miss" key=b`,
+		`level=DEBUG msg="memoizer entry evicted" key=a`,
+		`level=DEBUG msg="memoizer cache miss" key=c`,
+		`level=DEBUG msg="memoizer computation panicked" key=c panic=boom`,
+	}, strings.Split(strings.TrimSpace(out.String()), "\n"))
+	assert.True(t, memoizer.Config().Logging)
+}
@@ -0,0 +1,239 @@
+package memoizer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRUStore is an in-memory Store that holds at most a fixed number of entries, or entries of
+// at most a fixed total cost, evicting the least recently used entries to make room for a new
+// one. It implements InspectableStore and EvictionNotifier.
+type LRUStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxCost    int64
+	cost       func(value interface{}) int64
+	totalCost  int64
+	items      map[string]*list.Element
+	order      *list.List // of *lruEntry, most recently used first
+	onEvicted  func(key string, value interface{})
+}
+
+// Sizer is implemented by cached values that know their approximate size in bytes. An
+// LRUStore bounded by cost uses it when no cost function is given.
+type Sizer interface {
+	Size() int64
+}
+
+// lruEntry is an entry of an LRUStore.
+type lruEntry struct {
+	key       string
+	value     interface{}
+	cost      int64
+	expiresAt time.Time // zero if the entry never expires
+}
+
+// expired reports whether the entry has expired at now.
+func (e *lruEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// NewLRUStore creates and returns a new LRUStore holding at most maxEntries entries, or any
+// number of entries if maxEntries is not positive.
+func NewLRUStore(maxEntries int) *LRUStore {
+	return NewLRUStoreWithCost(maxEntries, 0, nil)
+}
+
+// NewLRUStoreWithCost is like NewLRUStore, but additionally bounds the total cost of the
+// entries to maxCost, if it is positive. The cost of each entry is reported by cost, such as
+// its approximate size in bytes; if cost is nil, values implementing Sizer report their size
+// and other values cost 1. An entry that costs more than maxCost on its own is not stored.
+func NewLRUStoreWithCost(maxEntries int, maxCost int64, cost func(value interface{}) int64) *LRUStore {
+	if cost == nil {
+		cost = defaultCost
+	}
+	return &LRUStore{
+		maxEntries: maxEntries,
+		maxCost:    maxCost,
+		cost:       cost,
+		items:      make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// defaultCost is the cost function of an LRUStore created without one.
+func defaultCost(value interface{}) int64 {
+	if sizer, ok := value.(Sizer); ok {
+		return sizer.Size()
+	}
+	return 1
+}
+
+// Get returns the value stored under key, unless it is missing or has expired, and marks the
+// entry as recently used.
+func (s *LRUStore) Get(key string) (interface{}, bool) {
+	value, _, ok := s.GetWithExpiration(key)
+	return value, ok
+}
+
+// GetWithExpiration is like Get, but also returns the time the entry expires, or the zero time
+// if it never expires.
+func (s *LRUStore) GetWithExpiration(key string) (interface{}, time.Time, bool) {
+	s.mu.Lock()
+	element, ok := s.items[key]
+	if !ok {
+		s.mu.Unlock()
+		return nil, time.Time{}, false
+	}
+	entry := element.Value.(*lruEntry)
+	if entry.expired(time.Now()) {
+		s.removeElement(element)
+		s.mu.Unlock()
+		s.evicted([]*lruEntry{entry})
+		return nil, time.Time{}, false
+	}
+	s.order.MoveToFront(element)
+	s.mu.Unlock()
+	return entry.value, entry.expiresAt, true
+}
+
+// Set stores value under key as the most recently used entry, evicting the least recently
+// used entries if the store is full. The entry expires after ttl, or never if ttl is not
+// positive.
+func (s *LRUStore) Set(key string, value interface{}, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	cost := s.cost(value)
+
+	s.mu.Lock()
+	if s.maxCost > 0 && cost > s.maxCost {
+		// The entry can never fit; drop the one it replaces instead of evicting everything else.
+		var evicted []*lruEntry
+		if element, ok := s.items[key]; ok {
+			s.removeElement(element)
+			evicted = append(evicted, element.Value.(*lruEntry))
+		}
+		s.mu.Unlock()
+		s.evicted(evicted)
+		return
+	}
+	if element, ok := s.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		s.totalCost += cost - entry.cost
+		entry.value, entry.cost, entry.expiresAt = value, cost, expiresAt
+		s.order.MoveToFront(element)
+	} else {
+		s.items[key] = s.order.PushFront(&lruEntry{key: key, value: value, cost: cost, expiresAt: expiresAt})
+		s.totalCost += cost
+	}
+	evicted := s.evict()
+	s.mu.Unlock()
+	s.evicted(evicted)
+}
+
+// Delete removes the entry stored under key, if any.
+func (s *LRUStore) Delete(key string) {
+	s.mu.Lock()
+	element, ok := s.items[key]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	s.removeElement(element)
+	s.mu.Unlock()
+	s.evicted([]*lruEntry{element.Value.(*lruEntry)})
+}
+
+// Flush removes all entries, without reporting them to the eviction callback.
+func (s *LRUStore) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = make(map[string]*list.Element)
+	s.order.Init()
+	s.totalCost = 0
+}
+
+// Entries returns a copy of all unexpired entries. It does not affect their recency.
+func (s *LRUStore) Entries() map[string]StoreEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	entries := make(map[string]StoreEntry, len(s.items))
+	for key, element := range s.items {
+		entry := element.Value.(*lruEntry)
+		if !entry.expired(now) {
+			entries[key] = StoreEntry{Value: entry.value, ExpiresAt: entry.expiresAt}
+		}
+	}
+	return entries
+}
+
+// Range calls fn with each unexpired entry until fn returns false, without copying them. It
+// does not affect their recency. The store is locked until Range returns.
+func (s *LRUStore) Range(fn func(key string, entry StoreEntry) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for key, element := range s.items {
+		entry := element.Value.(*lruEntry)
+		if !entry.expired(now) && !fn(key, StoreEntry{Value: entry.value, ExpiresAt: entry.expiresAt}) {
+			return
+		}
+	}
+}
+
+// flushMatch removes the entries whose key match reports true, without reporting them to the
+// eviction callback.
+func (s *LRUStore) flushMatch(match func(key string) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, element := range s.items {
+		if match(key) {
+			s.removeElement(element)
+		}
+	}
+}
+
+// OnEvicted registers fn to be called with every entry that is evicted, expires or is deleted.
+func (s *LRUStore) OnEvicted(fn func(key string, value interface{})) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onEvicted = fn
+}
+
+// evict removes least recently used entries until the store is within its bounds, and returns
+// them. The caller must hold s.mu.
+func (s *LRUStore) evict() []*lruEntry {
+	var evicted []*lruEntry
+	for (s.maxEntries > 0 && s.order.Len() > s.maxEntries) || (s.maxCost > 0 && s.totalCost > s.maxCost) {
+		element := s.order.Back()
+		s.removeElement(element)
+		evicted = append(evicted, element.Value.(*lruEntry))
+	}
+	return evicted
+}
+
+// removeElement removes an entry. The caller must hold s.mu.
+func (s *LRUStore) removeElement(element *list.Element) {
+	entry := element.Value.(*lruEntry)
+	s.order.Remove(element)
+	delete(s.items, entry.key)
+	s.totalCost -= entry.cost
+}
+
+// evicted reports removed entries to the eviction callback. It must be called without holding
+// s.mu, so that the callback may use the store.
+func (s *LRUStore) evicted(entries []*lruEntry) {
+	s.mu.Lock()
+	onEvicted := s.onEvicted
+	s.mu.Unlock()
+	if onEvicted == nil {
+		return
+	}
+	for _, entry := range entries {
+		onEvicted(entry.key, entry.value)
+	}
+}
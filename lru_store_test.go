@@ -0,0 +1,144 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUStore(t *testing.T) {
+	store := NewLRUStore(2)
+	var evicted []string
+	store.OnEvicted(func(key string, _ interface{}) {
+		evicted = append(evicted, key)
+	})
+
+	store.Set("a", 1, NoExpiration)
+	store.Set("b", 2, NoExpiration)
+	// Reading a makes b the least recently used entry.
+	value, ok := store.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	store.Set("c", 3, NoExpiration)
+	assert.Equal(t, []string{"b"}, evicted)
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+	assert.Len(t, store.Entries(), 2)
+
+	// Overwriting an entry does not evict anything.
+	store.Set("a", 10, NoExpiration)
+	assert.Equal(t, []string{"b"}, evicted)
+
+	store.Delete("a")
+	assert.Equal(t, []string{"b", "a"}, evicted)
+
+	store.Flush()
+	assert.Empty(t, store.Entries())
+}
+
+func TestLRUStoreExpiration(t *testing.T) {
+	store := NewLRUStore(0)
+	store.Set("short", 1, 10*time.Millisecond)
+	store.Set("long", 2, time.Minute)
+
+	_, expiresAt, ok := store.GetWithExpiration("long")
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok = store.Get("short")
+	assert.False(t, ok)
+	assert.Len(t, store.Entries(), 1)
+}
+
+func TestLRUStoreRange(t *testing.T) {
+	store := NewLRUStore(0)
+	store.Set("a", 1, 0)
+	store.Set("b", 2, 0)
+	store.Set("expired", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	seen := make(map[string]interface{})
+	store.Range(func(key string, entry StoreEntry) bool {
+		seen[key] = entry.Value
+		return true
+	})
+	assert.Equal(t, map[string]interface{}{"a": 1, "b": 2}, seen)
+
+	visits := 0
+	store.Range(func(string, StoreEntry) bool {
+		visits++
+		return false
+	})
+	assert.Equal(t, 1, visits, "returning false should stop the iteration")
+}
+
+func TestMemoizerWithMaxEntries(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithMaxEntries(2))
+	for i, key := range []string{"a", "b", "c"} {
+		i := i
+		_, err := memoizer.Memoize(key, func() (int, error) { return i, nil })
+		require.NoError(t, err)
+	}
+
+	inventory := memoizer.Inventory()
+	require.Len(t, inventory, 2)
+	assert.Equal(t, "b", inventory[0].Key)
+	assert.Equal(t, "c", inventory[1].Key)
+	// Evicted entries drop their access statistics.
+	assert.NotContains(t, memoizer.access.keys(), "a")
+}
+
+type sizedValue int64
+
+func (v sizedValue) Size() int64 {
+	return int64(v)
+}
+
+func TestLRUStoreWithCost(t *testing.T) {
+	store := NewLRUStoreWithCost(0, 100, nil)
+
+	store.Set("a", sizedValue(40), NoExpiration)
+	store.Set("b", sizedValue(40), NoExpiration)
+	store.Set("c", sizedValue(40), NoExpiration)
+	// a is evicted to bring the total cost from 120 back within 100.
+	assert.ElementsMatch(t, []string{"b", "c"}, keysOf(store.Entries()))
+
+	// Growing an entry evicts others as needed.
+	store.Set("c", sizedValue(90), NoExpiration)
+	assert.ElementsMatch(t, []string{"c"}, keysOf(store.Entries()))
+
+	// An entry that can never fit is not stored, and drops the entry it replaces.
+	store.Set("d", sizedValue(10), NoExpiration)
+	store.Set("d", sizedValue(200), NoExpiration)
+	assert.ElementsMatch(t, []string{"c"}, keysOf(store.Entries()))
+
+	store.Flush()
+	store.Set("e", sizedValue(100), NoExpiration)
+	assert.ElementsMatch(t, []string{"e"}, keysOf(store.Entries()))
+}
+
+func TestMemoizerWithMaxCost(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[string](WithMaxCost(10, func(value interface{}) int64 {
+		return int64(len(value.(string)))
+	}))
+	for _, key := range []string{"aaaa", "bbbb", "cccc"} {
+		key := key
+		_, err := memoizer.Memoize(key, func() (string, error) { return key, nil })
+		require.NoError(t, err)
+	}
+
+	assert.ElementsMatch(t, []string{"bbbb", "cccc"}, keysOf(memoizer.store.(InspectableStore).Entries()))
+	assert.Equal(t, int64(10), memoizer.Config().MaxCost)
+}
+
+func keysOf(entries map[string]StoreEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
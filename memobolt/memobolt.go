@@ -0,0 +1,262 @@
+// Package memobolt provides a memoizer.Store backed by an embedded bbolt database, so that
+// memoized results can exceed memory and survive restarts without an external server.
+//
+// Values are serialized with a memoizer.Codec, JSON by default, and stored together with their
+// expiration time. Expired entries are treated as missing and are removed when overwritten, or
+// in bulk with Purge. Because a Store cannot return errors, database and codec failures are
+// reported to an optional error handler: a failed read is treated as a cache miss, and a failed
+// write leaves the value uncached.
+package memobolt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KevinWang15/memoizer"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultBucket is the bucket entries are stored in unless WithBucket is given.
+const DefaultBucket = "memoizer"
+
+// Store is a memoizer.InspectableStore that keeps values of type T in a bbolt database.
+type Store[T any] struct {
+	db      *bolt.DB
+	bucket  []byte
+	codec   memoizer.Codec
+	onError func(err error)
+}
+
+// Option configures a Store.
+type Option[T any] func(s *Store[T])
+
+// WithBucket stores entries in the named bucket, so that several Memoizers can share one
+// database and Flush only removes the Store's own entries.
+func WithBucket[T any](bucket string) Option[T] {
+	return func(s *Store[T]) {
+		s.bucket = []byte(bucket)
+	}
+}
+
+// WithCodec replaces the default JSON serialization.
+func WithCodec[T any](codec memoizer.Codec) Option[T] {
+	return func(s *Store[T]) {
+		s.codec = codec
+	}
+}
+
+// WithErrorHandler registers a function that is called with every database or codec error.
+func WithErrorHandler[T any](onError func(err error)) Option[T] {
+	return func(s *Store[T]) {
+		s.onError = onError
+	}
+}
+
+// New creates and returns a new Store that uses db, creating its bucket if needed.
+func New[T any](db *bolt.DB, options ...Option[T]) (*Store[T], error) {
+	s := &Store[T]{db: db, bucket: []byte(DefaultBucket), codec: memoizer.JSONCodec{}}
+	for _, option := range options {
+		option(s)
+	}
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(s.bucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("memobolt: create bucket: %w", err)
+	}
+	return s, nil
+}
+
+// Get implements memoizer.Store.
+func (s *Store[T]) Get(key string) (interface{}, bool) {
+	value, _, ok := s.GetWithExpiration(key)
+	return value, ok
+}
+
+// GetWithExpiration implements memoizer.InspectableStore.
+func (s *Store[T]) GetWithExpiration(key string) (interface{}, time.Time, bool) {
+	var (
+		value     T
+		expiresAt time.Time
+		found     bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(s.bucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		var err error
+		value, expiresAt, err = s.decode(data)
+		if err != nil {
+			return fmt.Errorf("memobolt: decode %q: %w", key, err)
+		}
+		found = expiresAt.IsZero() || time.Now().Before(expiresAt)
+		return nil
+	})
+	if err != nil {
+		s.error(err)
+		return nil, time.Time{}, false
+	}
+	if !found {
+		return nil, time.Time{}, false
+	}
+	return value, expiresAt, true
+}
+
+// Set implements memoizer.Store. A negative ttl stores the value without expiration.
+func (s *Store[T]) Set(key string, value interface{}, ttl time.Duration) {
+	typed, ok := value.(T)
+	if !ok && value != nil {
+		s.error(fmt.Errorf("memobolt: set %q: unexpected value type %T", key, value))
+		return
+	}
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	data, err := s.encode(typed, expiresAt)
+	if err != nil {
+		s.error(fmt.Errorf("memobolt: encode %q: %w", key, err))
+		return
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(key), data)
+	})
+	if err != nil {
+		s.error(fmt.Errorf("memobolt: set %q: %w", key, err))
+	}
+}
+
+// Delete implements memoizer.Store.
+func (s *Store[T]) Delete(key string) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(key))
+	})
+	if err != nil {
+		s.error(fmt.Errorf("memobolt: delete %q: %w", key, err))
+	}
+}
+
+// Flush implements memoizer.Store. It removes every entry in the Store's bucket.
+func (s *Store[T]) Flush() {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(s.bucket); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		_, err := tx.CreateBucket(s.bucket)
+		return err
+	})
+	if err != nil {
+		s.error(fmt.Errorf("memobolt: flush: %w", err))
+	}
+}
+
+// Entries implements memoizer.InspectableStore. It decodes every unexpired entry into memory,
+// so it is best avoided on stores much larger than memory; Range visits them one at a time.
+func (s *Store[T]) Entries() map[string]memoizer.StoreEntry {
+	entries := make(map[string]memoizer.StoreEntry)
+	s.Range(func(key string, entry memoizer.StoreEntry) bool {
+		entries[key] = entry
+		return true
+	})
+	return entries
+}
+
+// errStopRange stops the iteration of Range.
+var errStopRange = errors.New("memobolt: stop range")
+
+// Range implements memoizer.InspectableStore. It decodes the unexpired entries one at a time
+// within a read transaction, which lasts until Range returns.
+func (s *Store[T]) Range(fn func(key string, entry memoizer.StoreEntry) bool) {
+	now := time.Now()
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).ForEach(func(key, data []byte) error {
+			value, expiresAt, err := s.decode(data)
+			if err != nil {
+				s.error(fmt.Errorf("memobolt: decode %q: %w", key, err))
+				return nil
+			}
+			if (expiresAt.IsZero() || now.Before(expiresAt)) && !fn(string(key), memoizer.StoreEntry{Value: value, ExpiresAt: expiresAt}) {
+				return errStopRange
+			}
+			return nil
+		})
+	})
+	if err != nil && !errors.Is(err, errStopRange) {
+		s.error(fmt.Errorf("memobolt: range: %w", err))
+	}
+}
+
+// Purge removes every expired entry from the database.
+func (s *Store[T]) Purge() error {
+	now := time.Now()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		// Deleting while iterating with a cursor skips entries, so collect the keys first.
+		var expired [][]byte
+		err := bucket.ForEach(func(key, data []byte) error {
+			if expiresAt := expiration(data); !expiresAt.IsZero() && !now.Before(expiresAt) {
+				expired = append(expired, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("memobolt: purge: %w", err)
+	}
+	return nil
+}
+
+// encode lays out an entry as its expiration time in Unix nanoseconds, or zero, followed by
+// the serialized value.
+func (s *Store[T]) encode(value T, expiresAt time.Time) ([]byte, error) {
+	payload, err := s.codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 8, 8+len(payload))
+	if !expiresAt.IsZero() {
+		binary.BigEndian.PutUint64(data, uint64(expiresAt.UnixNano()))
+	}
+	return append(data, payload...), nil
+}
+
+// decode reverses encode.
+func (s *Store[T]) decode(data []byte) (T, time.Time, error) {
+	var value T
+	if len(data) < 8 {
+		return value, time.Time{}, errors.New("entry too short")
+	}
+	err := s.codec.Unmarshal(data[8:], &value)
+	return value, expiration(data), err
+}
+
+// expiration returns the expiration time of an encoded entry.
+func expiration(data []byte) time.Time {
+	if len(data) < 8 {
+		return time.Time{}
+	}
+	nanos := binary.BigEndian.Uint64(data)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(nanos))
+}
+
+func (s *Store[T]) error(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
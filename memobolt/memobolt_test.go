@@ -0,0 +1,113 @@
+package memobolt
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+type user struct {
+	Name string
+	Age  int
+}
+
+func openDB(t *testing.T, path string) *bolt.DB {
+	db, err := bolt.Open(path, 0o600, nil)
+	require.NoError(t, err)
+	return db
+}
+
+func TestStore(t *testing.T) {
+	db := openDB(t, filepath.Join(t.TempDir(), "cache.db"))
+	defer db.Close()
+	store, err := New[user](db)
+	require.NoError(t, err)
+
+	store.Set("alice", user{"Alice", 30}, time.Minute)
+	store.Set("bob", user{"Bob", 40}, memoizer.NoExpiration)
+
+	value, expiresAt, ok := store.GetWithExpiration("alice")
+	require.True(t, ok)
+	assert.Equal(t, user{"Alice", 30}, value)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+	_, expiresAt, ok = store.GetWithExpiration("bob")
+	require.True(t, ok)
+	assert.True(t, expiresAt.IsZero())
+	assert.Len(t, store.Entries(), 2)
+
+	store.Delete("alice")
+	_, ok = store.Get("alice")
+	assert.False(t, ok)
+
+	store.Flush()
+	assert.Empty(t, store.Entries())
+}
+
+func TestStoreExpiration(t *testing.T) {
+	db := openDB(t, filepath.Join(t.TempDir(), "cache.db"))
+	defer db.Close()
+	store, err := New[int](db, WithBucket[int]("numbers"), WithCodec[int](memoizer.GobCodec{}))
+	require.NoError(t, err)
+
+	store.Set("short", 1, 10*time.Millisecond)
+	store.Set("long", 2, time.Minute)
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok := store.Get("short")
+	assert.False(t, ok)
+	require.NoError(t, store.Purge())
+	err = db.View(func(tx *bolt.Tx) error {
+		assert.Equal(t, 1, tx.Bucket([]byte("numbers")).Stats().KeyN)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestStoreSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	db := openDB(t, path)
+	store, err := New[user](db)
+	require.NoError(t, err)
+	m := memoizer.NewMemoizerWithOptions[user](memoizer.WithStore(store))
+	_, err = m.Memoize("alice", func() (user, error) { return user{"Alice", 30}, nil })
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db = openDB(t, path)
+	defer db.Close()
+	store, err = New[user](db)
+	require.NoError(t, err)
+	m = memoizer.NewMemoizerWithOptions[user](memoizer.WithStore(store))
+	result, err := m.Memoize("alice", func() (user, error) {
+		t.Fatal("the persisted value should be used")
+		return user{}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, user{"Alice", 30}, result)
+}
+
+func TestStoreErrors(t *testing.T) {
+	db := openDB(t, filepath.Join(t.TempDir(), "cache.db"))
+	defer db.Close()
+	var errs []error
+	store, err := New[int](db, WithErrorHandler[int](func(err error) {
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+
+	store.Set("key", "not an int", time.Minute)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "unexpected value type string")
+
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(DefaultBucket)).Put([]byte("corrupt"), []byte("x"))
+	}))
+	_, ok := store.Get("corrupt")
+	assert.False(t, ok)
+	assert.Len(t, errs, 2)
+}
@@ -1,7 +1,14 @@
 package memoizer
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -11,75 +18,1086 @@ import (
 // Memoizer is a structure that provides memoization capabilities.
 // It stores results of expensive function calls and returns the cached result when possible.
 type Memoizer[T any] struct {
+	mu                sync.Mutex // serializes operations that touch several entries, such as Rename
 	singleFlightGroup singleflight.Group
-	cache             *cache.Cache
+	multiFlightGroup  singleflight.Group  // deduplicates MemoizeMulti computations by primary key
+	multiKeys         map[string][]string // keys produced by the last MemoizeMulti computation of each primary key
+	store             Store
+	defaultExpiration time.Duration
+	cleanupInterval   time.Duration
+	ownsStore         bool // whether the store was created by New, rather than passed to it
+	closeOnce         sync.Once
+	activity          activity      // of calls, for ShutdownAndPersist
+	generation        atomic.Uint64 // incremented by invalidations, to drop late results of WithTimeout
+	admission         admissionFilter
+	access            accessTracker
+	circuit           *globalCircuit
+	keyCircuits       *keyCircuits
+	autoRefresher     autoRefresher
+	cloneValue        func(value T) T
+	mutations         *mutationDetector
+	strictTypes       bool
+	validateOptions   bool
+	keyHasher         func(key string) string
+	waiters           waiterCounts
+	computations      chan struct{} // semaphore of WithMaxConcurrentComputations
+	defaultOptions    []Option
+	decode            func(raw interface{}) (T, bool)
+	recorder          *Recorder
+	reentrancy        reentrancyGuard
+	windowedStats     *windowedStats
+	errors            errorCache
+	tags              tagIndex
+	counters          statsCounters
+	observeCompute    func(key string, duration time.Duration, err error)
+	tracer            CallTracer
+	hooks             Hooks[T]
+	logger            *slog.Logger
+	codec             Codec
+	persistence       *persistence
+	broadcaster       Broadcaster
+	instanceID        string
+	lock              *distributedLock
+	coalescer         *invalidationCoalescer
 }
 
-type unwrappableErr interface {
-	Unwrap() error
+// ErrTypeMismatch is the error a Memoizer with WithStrictTypes panics with when the store holds
+// a value for a key that is not of type T, such as when Memoizers of different types share a
+// store and a key.
+var ErrTypeMismatch = errors.New("cache value type mismatch")
+
+// NoExpiration is used as a duration to indicate that a cached entry never expires.
+const NoExpiration = cache.NoExpiration
+
+// New creates and returns a new instance of a Memoizer configured by the given constructor
+// options, which can be freely combined, such as WithDefaultExpiration, WithCleanupInterval,
+// WithStore, WithHooks or WithLogger. Without options, cached results never expire. Options
+// that only apply to individual Memoize calls are ignored.
+//
+// Example usage:
+//
+//	users := memoizer.New[User](memoizer.WithDefaultExpiration(time.Minute), memoizer.WithLogger(logger))
+func New[T any](options ...Option) *Memoizer[T] {
+	var (
+		store             Store
+		maxEntries        int
+		maxCost           int64
+		cost              func(value interface{}) int64
+		defaultExpiration = NoExpiration
+		cleanupInterval   time.Duration
+	)
+	for _, option := range options {
+		switch opt := option.(type) {
+		case *StoreOption:
+			store = opt.Store
+		case *MaxEntriesOption:
+			maxEntries = opt.MaxEntries
+		case *MaxCostOption:
+			maxCost, cost = opt.MaxCost, opt.Cost
+		case *DefaultExpirationOption:
+			defaultExpiration = opt.Expiration
+		case *CleanupIntervalOption:
+			cleanupInterval = opt.Interval
+		}
+	}
+	ownsStore := store == nil
+	if store == nil && (maxEntries > 0 || maxCost > 0) {
+		store = NewLRUStoreWithCost(maxEntries, maxCost, cost)
+	}
+	if store == nil && cleanupInterval > 0 {
+		store = NewGoCacheStore(cleanupInterval)
+	}
+	m := newMemoizer[T](defaultExpiration, store)
+	m.ownsStore = ownsStore
+	if _, ok := m.store.(*GoCacheStore); ok {
+		m.cleanupInterval = cleanupInterval
+	}
+	for _, option := range options {
+		switch opt := option.(type) {
+		case *GlobalCircuitOption:
+			minRequests := opt.MinRequests
+			if minRequests <= 0 {
+				minRequests = DefaultCircuitMinRequests
+			}
+			m.circuit = &globalCircuit{errorRate: opt.ErrorRate, window: opt.Window, cooldown: opt.Cooldown, minRequests: minRequests}
+		case *FlexibleDecodeOption[T]:
+			m.decode = opt.Decode
+		case *RecorderOption:
+			m.recorder = opt.Recorder
+		case *MaxRecursionDepthOption:
+			m.reentrancy.maxDepth = opt.Depth
+		case *WindowedStatsOption:
+			m.windowedStats = newWindowedStats()
+		case *ComputeObserverOption:
+			m.observeCompute = opt.Observe
+		case *CallTracerOption:
+			m.tracer = opt.Tracer
+		case *HooksOption[T]:
+			m.hooks = opt.Hooks
+		case *LoggerOption:
+			m.logger = opt.Logger
+		case *CodecOption:
+			m.codec = opt.Codec
+		case *MaxConcurrentComputationsOption:
+			if opt.MaxConcurrent > 0 {
+				m.computations = make(chan struct{}, opt.MaxConcurrent)
+			}
+		case *MaxTagsPerEntryOption:
+			m.tags.maxPerEntry = opt.MaxTags
+		case *MaxDistinctTagsOption:
+			m.tags.maxDistinct = opt.MaxTags
+		case *MaxErrorEntriesOption:
+			m.errors.max = opt.MaxErrorEntries
+		case *DefaultCallOptionsOption:
+			m.defaultOptions = append(m.defaultOptions, opt.Options...)
+		case *StrictTypesOption:
+			m.strictTypes = true
+		case *OptionValidationOption:
+			m.validateOptions = true
+		case *KeyHasherOption:
+			m.keyHasher = opt.Hasher
+		case *MutationDetectionOption:
+			m.mutations = &mutationDetector{onMutation: opt.OnMutation}
+		case *CloneOption[T]:
+			m.cloneValue = opt.Clone
+		case *CircuitBreakerOption:
+			m.keyCircuits = &keyCircuits{failures: opt.Failures, cooldown: opt.Cooldown}
+		case *CoalescedInvalidationOption:
+			m.coalescer = nil
+			if opt.Window > 0 {
+				m.coalescer = &invalidationCoalescer{window: opt.Window}
+			}
+		case *DistributedLockOption:
+			m.lock = &distributedLock{locker: opt.Locker, ttl: opt.TTL, pollInterval: opt.PollInterval}
+			if m.lock.pollInterval <= 0 {
+				m.lock.pollInterval = defaultLockPollInterval
+			}
+		case *BroadcasterOption:
+			m.broadcaster = opt.Broadcaster
+			m.instanceID = newInstanceID()
+			m.broadcaster.Subscribe(m.receive)
+		}
+	}
+	for _, option := range options {
+		if opt, ok := option.(*PersistenceOption); ok {
+			m.startPersistence(opt)
+		}
+	}
+	return m
 }
 
 // NewMemoizer creates and returns a new instance of a Memoizer.
+//
+// Deprecated: Use New.
 func NewMemoizer[T any]() *Memoizer[T] {
-	return &Memoizer[T]{
-		singleFlightGroup: singleflight.Group{},
-		cache:             cache.New(cache.NoExpiration, 0), // Initializes the cache with no expiration.
-	}
+	return New[T]()
 }
 
 // NewMemoizerWithCacheExpiration creates and returns a new instance of a Memoizer with a specified cache expiration time.
+//
+// Deprecated: Use New with WithDefaultExpiration.
 func NewMemoizerWithCacheExpiration[T any](expiration time.Duration) *Memoizer[T] {
-	return &Memoizer[T]{
+	return New[T](WithDefaultExpiration(expiration))
+}
+
+// NewMemoizerWithOptions creates and returns a new instance of a Memoizer configured by the
+// given constructor options.
+//
+// Deprecated: Use New, which takes the same options.
+func NewMemoizerWithOptions[T any](options ...Option) *Memoizer[T] {
+	return New[T](options...)
+}
+
+// DefaultExpiration returns the expiration applied to cached results that do not specify
+// their own, as configured at construction. A Memoizer whose results never expire by default
+// reports NoExpiration; this includes one constructed with an expiration of zero.
+func (m *Memoizer[T]) DefaultExpiration() time.Duration {
+	return m.defaultExpiration
+}
+
+// newMemoizer wires a Memoizer around store, or around a new GoCacheStore if store is nil,
+// with the given default expiration.
+func newMemoizer[T any](defaultExpiration time.Duration, store Store) *Memoizer[T] {
+	if defaultExpiration <= 0 {
+		// A zero default expiration has always meant no expiration.
+		defaultExpiration = NoExpiration
+	}
+	if store == nil {
+		store = NewGoCacheStore(0)
+	}
+	m := &Memoizer[T]{
 		singleFlightGroup: singleflight.Group{},
-		cache:             cache.New(expiration, 0), // Initializes the cache with the specified expiration.
+		store:             store,
+		defaultExpiration: defaultExpiration,
+		codec:             JSONCodec{},
 	}
+	if notifier, ok := store.(EvictionNotifier); ok {
+		notifier.OnEvicted(func(key string, value interface{}) {
+			m.access.remove(key)
+			m.tags.remove(key)
+			if _, deleting := m.counters.deleting.Load(key); !deleting {
+				m.counters.evictions.Add(1)
+				if m.logger != nil {
+					m.logger.Debug("memoizer entry evicted", "key", key)
+				}
+				if m.hooks.OnEvict != nil {
+					evicted, _ := value.(T)
+					m.hooks.OnEvict(key, evicted)
+				}
+			}
+		})
+	}
+	return m
 }
 
 // Memoize checks the cache for a stored result for the given key. If not found, it executes the function,
 // caches its result, and returns it. This method ensures that concurrent calls with the same key
 // do not result in multiple executions of the function.
+//
+// A result is cached if and only if the function returns a nil error. For an interface-typed T,
+// this includes a nil result: (nil, nil) is cached as a valid nil value, while (nil, err) is not.
+// WithErrorCaching additionally caches errors for a while; a cached error is returned to every
+// caller of the key until it expires or a value is stored for the key.
+//
+// A function that requests its own key again on the goroutine computing it gets
+// ErrReentrantComputation rather than waiting on itself forever.
 func (m *Memoizer[T]) Memoize(key string, fn func() (T, error), options ...Option) (T, error) {
-	// Attempt to retrieve the cached value.
-	value, ok := m.cache.Get(key)
-	if ok {
-		// If a value is found, assert its type and return it.
-		typedValue, ok := value.(T)
-		if !ok {
-			panic(fmt.Errorf("cache value type mismatch"))
+	value, _, _, err := m.memoize(m.hashKey(key), fn, m.resolve(options))
+	return value, err
+}
+
+// memoize implements Memoize. It also reports where the value came from, and whether its
+// computation was shared with other callers.
+func (m *Memoizer[T]) memoize(key string, fn func() (T, error), opts callOptions[T]) (value T, source Source, shared bool, err error) {
+	if opts.invalid != nil {
+		return value, SourceNone, false, opts.invalid
+	}
+	if !m.activity.enter() {
+		return value, SourceNone, false, ErrClosed
+	}
+	defer m.activity.leave()
+	if opts.backend != nil {
+		value, source, shared, err = m.memoizeWithBackend(context.Background(), key, fn, opts)
+		return value, source, shared, repanic(err, opts)
+	}
+	// Attempt to retrieve the cached value, unless it is to be replaced.
+	if !opts.forceRefresh {
+		if value, ok := m.cached(key, fn, opts); ok {
+			m.traceHit(context.Background(), key)
+			return value, SourceCache, false, nil
+		}
+		if err, ok := m.errors.get(key); ok {
+			return value, SourceCache, false, err
 		}
-		return typedValue, nil
+		m.miss(key)
 	}
 
+	if value, err, open := m.circuitOpen(key, opts); open {
+		if err != ErrCircuitOpen {
+			source = SourceCache
+		}
+		return value, source, false, err
+	}
+
+	if opts.eagerBackgroundFill && !opts.forceRefresh {
+		// Return immediately and let a deduplicated background computation fill the cache.
+		// Errors and panics of the background computation are discarded.
+		m.background(key, fn, opts)
+		return value, SourceNone, false, nil
+	}
+
+	if m.reentrancy.reentrant(key) {
+		return value, SourceNone, false, ErrReentrantComputation
+	}
+
+	if opts.maxWaiters > 0 {
+		if !m.waiters.join(key, opts.maxWaiters) {
+			return value, SourceNone, false, ErrTooManyWaiters
+		}
+		defer m.waiters.leave(key)
+	}
+
+	// If no cached value is found, use singleflight to call the function and store its result.
+	// Only the computation of the caller that starts it runs; the others wait for it.
+	source = SourceShared
+	compute := m.computation(key, fn, opts)
+	end := m.traceMiss(context.Background(), key)
+	result, err, shared := m.singleFlightGroup.Do(key, func() (interface{}, error) {
+		source = SourceComputed
+		return compute()
+	})
+	end(err, shared)
+	if shared && m.logger != nil {
+		m.logger.Debug("memoizer computation shared", "key", key)
+	}
+	// Propagate the original panic value to every caller that shared the computation.
+	err = repanic(err, opts)
+	value, err = m.result(result, err)
+	return value, source, shared, err
+}
+
+// MemoizeWithAccess is like Memoize, but passes fn a get function for reading other cached
+// values, so a computation can be composed from already-cached siblings. get is a plain cache
+// read: it never triggers a computation, so calling it from fn cannot deadlock on a key that
+// is being computed, including fn's own key. It reports false for keys that are not cached.
+func (m *Memoizer[T]) MemoizeWithAccess(key string, fn func(get func(string) (T, bool)) (T, error), options ...Option) (T, error) {
+	return m.Memoize(key, func() (T, error) {
+		return fn(func(key string) (T, bool) {
+			return m.lookup(m.hashKey(key))
+		})
+	}, options...)
+}
+
+// Result holds the outcome of a memoized computation, so it can be passed on a channel.
+type Result[T any] struct {
+	Value T
+	Err   error
+	// Shared reports whether the result was delivered to more than one caller.
+	Shared bool
+}
+
+// MemoizeDoChan is like Memoize, but returns a channel that receives the result once it is
+// available instead of blocking. Concurrent callers for the same key share a single
+// computation, and successful results are cached as usual. A panic in fn is delivered as a
+// *PanicError rather than crashing the process. The channel is buffered, so the caller may
+// abandon it without leaking a goroutine.
+func (m *Memoizer[T]) MemoizeDoChan(key string, fn func() (T, error), options ...Option) <-chan Result[T] {
+	ch := make(chan Result[T], 1)
+	inner := m.doChan(context.Background(), m.hashKey(key), fn, m.resolve(options))
+	go func() {
+		r := <-inner
+		if p, ok := r.Err.(*recoveredPanic); ok {
+			r.Err = &PanicError{Value: p.value, Stack: p.stack}
+		}
+		ch <- r
+	}()
+	return ch
+}
+
+// MemoizeAsync is an alias of MemoizeDoChan, for callers that start a computation and select on
+// its result alongside other channels, such as ctx.Done().
+func (m *Memoizer[T]) MemoizeAsync(key string, fn func() (T, error), options ...Option) <-chan Result[T] {
+	return m.MemoizeDoChan(key, fn, options...)
+}
+
+// doChan is the implementation of MemoizeDoChan. A panic in fn is delivered as a
+// *recoveredPanic error, for the caller to re-panic or convert.
+func (m *Memoizer[T]) doChan(ctx context.Context, key string, fn func() (T, error), opts callOptions[T]) <-chan Result[T] {
+	ch := make(chan Result[T], 1)
+	if opts.invalid != nil {
+		ch <- Result[T]{Err: opts.invalid}
+		return ch
+	}
+	if !m.activity.enter() {
+		ch <- Result[T]{Err: ErrClosed}
+		return ch
+	}
+	// The call ends when its result is delivered, by the goroutine below if it starts one.
+	async := false
 	defer func() {
-		if r := recover(); r != nil {
-			if ue, ok := r.(unwrappableErr); ok {
-				panic(ue.Unwrap())
-			} else {
-				panic(r)
-			}
+		if !async {
+			m.activity.leave()
 		}
 	}()
 
-	// If no cached value is found, use singleflight to call the function and store its result.
-	result, err, _ := m.singleFlightGroup.Do(key, func() (interface{}, error) {
-		res, err := fn()
-		if err == nil {
-			// Cache the result if there's no error.
-			expiration := cache.DefaultExpiration
-			for _, option := range options {
-				if opt, ok := option.(*ExpirationOption); ok {
-					expiration = opt.Callback(res)
+	if opts.backend != nil {
+		async = true
+		go func() {
+			defer m.activity.leave()
+			value, _, shared, err := m.memoizeWithBackend(ctx, key, fn, opts)
+			ch <- Result[T]{Value: value, Err: err, Shared: shared}
+		}()
+		return ch
+	}
+
+	if !opts.forceRefresh {
+		if value, ok := m.cached(key, fn, opts); ok {
+			m.traceHit(ctx, key)
+			ch <- Result[T]{Value: value}
+			return ch
+		}
+		if err, ok := m.errors.get(key); ok {
+			ch <- Result[T]{Err: err}
+			return ch
+		}
+		m.miss(key)
+	}
+
+	if value, err, open := m.circuitOpen(key, opts); open {
+		ch <- Result[T]{Value: value, Err: err}
+		return ch
+	}
+
+	if m.reentrancy.reentrant(key) || computingFrom(ctx).includes(m, key) {
+		ch <- Result[T]{Err: ErrReentrantComputation}
+		return ch
+	}
+
+	if opts.maxWaiters > 0 && !m.waiters.join(key, opts.maxWaiters) {
+		ch <- Result[T]{Err: ErrTooManyWaiters}
+		return ch
+	}
+
+	end := m.traceMiss(ctx, key)
+	inner := m.singleFlightGroup.DoChan(key, m.computation(key, fn, opts))
+	async = true
+	go func() {
+		defer m.activity.leave()
+		r := <-inner
+		if opts.maxWaiters > 0 {
+			m.waiters.leave(key)
+		}
+		end(r.Err, r.Shared)
+		if r.Shared && m.logger != nil {
+			m.logger.Debug("memoizer computation shared", "key", key)
+		}
+		value, err := m.result(r.Val, r.Err)
+		ch <- Result[T]{Value: value, Err: err, Shared: r.Shared}
+	}()
+	return ch
+}
+
+// Set stores value under key, replacing any cached entry, for example to warm the cache before
+// traffic arrives. The entry expires after ttl; a ttl of zero stands for the default expiration,
+// and NoExpiration keeps the entry until it is removed.
+func (m *Memoizer[T]) Set(key string, value T, ttl time.Duration) {
+	m.put(m.hashKey(key), value, fixedExpiration[T](ttl))
+}
+
+// SetMany is like Set for every value in values, with the same ttl. Use SetAll for expirations
+// that depend on the value.
+func (m *Memoizer[T]) SetMany(values map[string]T, ttl time.Duration) {
+	opts := fixedExpiration[T](ttl)
+	for key, value := range values {
+		m.put(m.hashKey(key), value, opts)
+	}
+}
+
+// fixedExpiration returns the call options of Set and SetMany.
+func fixedExpiration[T any](ttl time.Duration) callOptions[T] {
+	return callOptions[T]{expiration: func(T) time.Duration { return ttl }}
+}
+
+// SetAll stores every value in values under its key, for example to warm the cache from a
+// precomputed dataset. Expiration options such as WithExpiration, WithMinTTL and WithMaxTTL
+// apply to each entry; options that only affect computations are ignored. Options are
+// resolved once for the whole batch, but go-cache offers no batched write, so each entry
+// still takes the cache lock individually.
+func (m *Memoizer[T]) SetAll(values map[string]T, options ...Option) {
+	opts := m.resolve(options)
+	for key, value := range values {
+		m.put(m.hashKey(key), value, opts)
+	}
+}
+
+// Rename moves the cached entry for oldKey, along with its remaining TTL, to newKey, replacing
+// any entry already stored under newKey. It also forgets any in-flight computation for oldKey.
+// Rename returns false, and changes nothing, if oldKey has no unexpired entry. If the store is
+// not an InspectableStore, the remaining TTL is unknown and the moved entry gets the default
+// expiration instead.
+func (m *Memoizer[T]) Rename(oldKey, newKey string) bool {
+	oldKey, newKey = m.hashKey(oldKey), m.hashKey(newKey)
+	if !m.rename(oldKey, newKey) {
+		return false
+	}
+	m.publishDeleted([]string{oldKey, newKey})
+	return true
+}
+
+// rename implements Rename, without publishing the invalidation of the two keys.
+func (m *Memoizer[T]) rename(oldKey, newKey string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		value     interface{}
+		expiresAt time.Time
+		ok        bool
+	)
+	if store, inspectable := m.store.(InspectableStore); inspectable {
+		value, expiresAt, ok = store.GetWithExpiration(oldKey)
+	} else {
+		value, ok = m.store.Get(oldKey)
+		if m.defaultExpiration > 0 {
+			expiresAt = time.Now().Add(m.defaultExpiration)
+		}
+	}
+	if !ok {
+		return false
+	}
+	expiration := NoExpiration
+	if !expiresAt.IsZero() {
+		if expiration = time.Until(expiresAt); expiration <= 0 {
+			return false
+		}
+	}
+
+	m.store.Set(newKey, value, expiration)
+	stat := m.access.get(oldKey)
+	if stat.storedAt.IsZero() {
+		stat.storedAt = time.Now()
+	}
+	m.access.stored(newKey, stat.storedAt, stat.freshness)
+	m.setTags(newKey, m.tags.tags(oldKey))
+	m.storeDelete(oldKey)
+	m.access.remove(oldKey)
+	m.tags.remove(oldKey)
+	m.singleFlightGroup.Forget(oldKey)
+	m.generation.Add(1)
+	return true
+}
+
+// Forget drops any in-flight computation for key without touching the cache, so that callers
+// that arrive afterwards start a fresh computation instead of sharing the current one, such as
+// when the data it reads has just changed. Callers already waiting still receive the forgotten
+// computation's result, which is still stored when it finishes. Use Delete to also drop the
+// cached entry.
+func (m *Memoizer[T]) Forget(key string) {
+	m.generation.Add(1)
+	m.singleFlightGroup.Forget(m.hashKey(key))
+}
+
+// Delete removes the cached entry for key, along with any error cached for it, so that the
+// next call recomputes it. It also forgets any in-flight computation for key: callers that
+// arrive afterwards start a fresh computation instead of sharing one that may have read the
+// data before it changed. The forgotten computation still stores its result when it finishes.
+// With WithCoalescedInvalidation, all of this happens at the end of the coalescing window.
+func (m *Memoizer[T]) Delete(key string) {
+	key = m.hashKey(key)
+	if m.coalescer != nil && m.coalescer.schedule(key, m.invalidate) {
+		return
+	}
+	m.invalidate(key)
+}
+
+// invalidate implements Delete for a key that has already been hashed.
+func (m *Memoizer[T]) invalidate(key string) {
+	m.mu.Lock()
+	m.delete(key)
+	m.mu.Unlock()
+
+	m.publish(Invalidation{Key: key})
+}
+
+// DeletePrefix is like Delete for every key that starts with prefix, such as all keys under
+// "user:123:".
+func (m *Memoizer[T]) DeletePrefix(prefix string) {
+	m.DeleteMatch(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// DeleteMatch is like Delete for every key for which match returns true. The keys are taken
+// from the store if it is an InspectableStore; otherwise only the keys stored by this Memoizer
+// are found.
+func (m *Memoizer[T]) DeleteMatch(match func(key string) bool) {
+	var deleted []string
+	m.mu.Lock()
+	for _, key := range m.keys() {
+		if match(key) {
+			m.delete(key)
+			deleted = append(deleted, key)
+		}
+	}
+	m.mu.Unlock()
+
+	m.publishDeleted(deleted)
+}
+
+// Flush removes every cached entry and cached error, and resets the access statistics reported
+// by Inventory. In-flight computations are not forgotten and store their results when they
+// finish.
+func (m *Memoizer[T]) Flush() {
+	m.mu.Lock()
+	m.store.Flush()
+	m.access.reset()
+	m.errors.reset()
+	m.tags.reset()
+	m.multiKeys = nil
+	m.generation.Add(1)
+	m.mu.Unlock()
+
+	m.publish(Invalidation{Flush: true})
+}
+
+// delete removes the cached entry and cached error for key and forgets its in-flight
+// computation. The caller must hold m.mu, and publish the deletion once it has released it:
+// a synchronous Broadcaster may deliver it to another Memoizer whose receive takes its own
+// lock, and that Memoizer may be publishing to this one at the same time.
+func (m *Memoizer[T]) delete(key string) {
+	m.storeDelete(key)
+	m.access.remove(key)
+	m.errors.remove(key)
+	m.tags.remove(key)
+	m.singleFlightGroup.Forget(key)
+	m.generation.Add(1)
+}
+
+// storeDelete removes key from the store without counting its removal as an eviction.
+func (m *Memoizer[T]) storeDelete(key string) {
+	m.counters.deleting.Store(key, struct{}{})
+	defer m.counters.deleting.Delete(key)
+	m.store.Delete(key)
+}
+
+// keys returns the keys of the entries in the store, or of the entries stored by this Memoizer
+// if the store cannot enumerate them, along with the keys of cached errors.
+func (m *Memoizer[T]) keys() []string {
+	seen := make(map[string]struct{})
+	if store, ok := m.store.(InspectableStore); ok {
+		for key := range store.Entries() {
+			seen[key] = struct{}{}
+		}
+	} else {
+		for _, key := range m.access.keys() {
+			seen[key] = struct{}{}
+		}
+	}
+	for _, key := range m.errors.keys() {
+		seen[key] = struct{}{}
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// cached returns the cached value for key, if any. If the value is stale, or is to be
+// recomputed ahead of its expiration, it is still returned, and a deduplicated background
+// computation is started to refresh it.
+func (m *Memoizer[T]) cached(key string, fn func() (T, error), opts callOptions[T]) (T, bool) {
+	value, ok := m.lookup(key)
+	stale := ok && m.stale(key)
+	if ok && !stale && opts.sliding {
+		m.slide(key, value, opts)
+	}
+	if stale || ok && opts.earlyBeta > 0 && m.access.expiresEarly(key, opts.earlyBeta) {
+		m.background(key, fn, opts)
+	}
+	return value, ok
+}
+
+// background starts a deduplicated computation of key without waiting for it; its result is
+// only needed in the cache. It is counted by m.activity from before the call starting it
+// returns, for ShutdownAndPersist to wait for.
+func (m *Memoizer[T]) background(key string, fn func() (T, error), opts callOptions[T]) {
+	m.activity.join()
+	// DoChan registers the computation before returning, so that concurrent callers share it.
+	done := m.singleFlightGroup.DoChan(key, m.computation(key, fn, opts))
+	go func() {
+		<-done
+		m.activity.leave()
+	}()
+}
+
+// stale reports whether the cached entry of key is being served stale while it is revalidated.
+func (m *Memoizer[T]) stale(key string) bool {
+	staleAt := m.access.freshness(key).staleAt
+	return !staleAt.IsZero() && time.Now().After(staleAt)
+}
+
+// Peek returns the value cached for key, if any, without computing it on a miss. It is a plain
+// read for fast-path checks: it does not count as a hit or miss in statistics, and does not
+// affect the entry's expiration.
+func (m *Memoizer[T]) Peek(key string) (T, bool) {
+	key = m.hashKey(key)
+	value, ok := m.retained(key)
+	if !ok || !m.servable(key) {
+		var zero T
+		return zero, false
+	}
+	return value, true
+}
+
+// servable reports whether the cached entry of key may be served, as opposed to only being
+// retained as a fallback for failed recomputations.
+func (m *Memoizer[T]) servable(key string) bool {
+	expiresAt := m.access.freshness(key).expiresAt
+	return expiresAt.IsZero() || !time.Now().After(expiresAt)
+}
+
+// miss records that a lookup of key missed the cache.
+func (m *Memoizer[T]) miss(key string) {
+	m.counters.misses.Add(1)
+	if m.windowedStats != nil {
+		m.windowedStats.miss()
+	}
+	if m.hooks.OnMiss != nil {
+		m.hooks.OnMiss(key)
+	}
+	if m.logger != nil {
+		m.logger.Debug("memoizer cache miss", "key", key)
+	}
+}
+
+// lookup returns the cached value for key, if any. An entry past its expiration that the
+// store only retains as a fallback for failed recomputations is a miss.
+func (m *Memoizer[T]) lookup(key string) (T, bool) {
+	typedValue, ok := m.retained(key)
+	if !ok {
+		return typedValue, false
+	}
+	if !m.servable(key) {
+		var zero T
+		return zero, false
+	}
+	m.access.hit(key)
+	m.counters.hits.Add(1)
+	if m.hooks.OnHit != nil {
+		m.hooks.OnHit(key, typedValue)
+	}
+	if m.logger != nil {
+		m.logger.Debug("memoizer cache hit", "key", key)
+	}
+	if m.windowedStats != nil {
+		m.windowedStats.hit()
+	}
+	if m.recorder != nil {
+		m.recorder.record(RecordedOp{Time: time.Now(), Key: key, Hit: true})
+	}
+	return typedValue, true
+}
+
+// retained returns the value the store holds for key, if any, without recording a hit and
+// regardless of whether the entry has expired.
+func (m *Memoizer[T]) retained(key string) (T, bool) {
+	value, ok := m.store.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	m.verify(key, value)
+	// If a value is found, assert its type and return it. A value that cannot be decoded
+	// into T is treated as a miss.
+	return m.typed(value)
+}
+
+// computation returns the function run by singleflight on a cache miss. It calls fn, caches
+// a successful result, and converts a panic in fn into a *recoveredPanic error so that it
+// can be handled by the caller instead of by singleflight.
+func (m *Memoizer[T]) computation(key string, fn func() (T, error), opts callOptions[T]) func() (interface{}, error) {
+	return func() (result interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				p, ok := r.(*recoveredPanic)
+				if !ok {
+					p = &recoveredPanic{value: r, stack: debug.Stack()}
 				}
+				if m.logger != nil {
+					m.logger.Debug("memoizer computation panicked", "key", key, "panic", p.value)
+				}
+				result, err = nil, p
+			}
+		}()
+
+		m.counters.inFlight.Add(1)
+		defer m.counters.inFlight.Add(-1)
+		m.activity.join()
+		defer m.activity.leave()
+
+		gid := goroutineID()
+		if err := m.reentrancy.enter(key, gid); err != nil {
+			return nil, err
+		}
+		defer m.reentrancy.exit(key, gid)
+
+		if m.lock != nil {
+			unlock, value, ok := m.awaitLock(key, opts)
+			if ok {
+				return value, nil
+			}
+			defer unlock()
+		}
+
+		release := m.acquireComputation()
+		defer release()
+
+		compute := fn
+		if opts.hedgeDelay > 0 {
+			compute = hedged(compute, opts.hedgeDelay)
+		}
+		if opts.timeout > 0 {
+			compute = m.withTimeout(key, fn, compute, opts)
+		}
+		start := time.Now()
+		res, err, fellBack := m.callRetrying(compute, opts)
+		for attempt := 1; opts.resultValid != nil && err == nil && !fellBack && !opts.resultValid(res); attempt++ {
+			if attempt >= opts.resultAttempts {
+				err = ErrResultInvalid
+				break
 			}
-			m.cache.Set(key, res, expiration)
+			res, err, fellBack = m.callRetrying(compute, opts)
+		}
+		duration := time.Since(start)
+		if m.recorder != nil {
+			m.recorder.record(RecordedOp{Time: start, Key: key, ComputeDuration: duration})
+		}
+		if m.observeCompute != nil {
+			m.observeCompute(key, duration, err)
+		}
+		if err != nil && m.hooks.OnError != nil {
+			m.hooks.OnError(key, err, duration)
+		}
+		if opts.auditLog != nil {
+			entry := AuditEntry{Key: key, Time: start, Duration: duration, Err: err}
+			if opts.auditSummarize != nil && err == nil {
+				entry.Summary = opts.auditSummarize(res)
+			}
+			opts.auditLog(entry)
+		}
+		if m.circuit != nil {
+			m.circuit.record(time.Now(), err != nil)
+		}
+		if m.keyCircuits != nil {
+			m.keyCircuits.record(key, time.Now(), err != nil)
+		}
+		if fellBack && !opts.cachePanicFallback {
+			return res, err
+		}
+		if err != nil && opts.staleOnErrorFor > 0 {
+			if stale, ok := m.retained(key); ok {
+				if opts.reportStaleOnError {
+					return stale, &StaleError{Err: err}
+				}
+				return stale, nil
+			}
+		}
+		if err != nil && opts.fallback != nil {
+			res, err = opts.fallback(key, err)
+			if err == nil && !opts.cacheFallback {
+				return res, nil
+			}
+		}
+		m.keep(key, fn, res, err, start, duration, opts)
+		if err == nil && opts.afterCompute != nil {
+			opts.afterCompute(key, res, func(k string, v T, options ...Option) {
+				m.put(m.hashKey(k), v, m.resolve(options))
+			})
 		}
 		return res, err
-	})
+	}
+}
+
+// keep caches the outcome of a computation of key with fn that started at start and took
+// duration: its result, if opts allow caching it, or its error, with WithErrorCaching or
+// WithMinRecomputeInterval.
+func (m *Memoizer[T]) keep(key string, fn func() (T, error), res T, err error, start time.Time, duration time.Duration, opts callOptions[T]) {
+	if err != nil {
+		var ttl time.Duration
+		if opts.errorTTL != nil {
+			ttl = opts.errorTTL(err)
+		}
+		// Replay the error until the key may be recomputed.
+		if remaining := opts.minRecompute - time.Since(start); remaining > ttl {
+			ttl = remaining
+		}
+		if ttl > 0 {
+			m.errors.set(key, err, ttl)
+		}
+		return
+	}
+	cacheable := opts.cachePredicate == nil || opts.cachePredicate(res)
+	if cacheable && (opts.admissionThreshold <= 1 || m.admission.admit(key, opts.admissionThreshold)) {
+		m.put(key, res, opts)
+		m.access.computed(key, duration)
+		if opts.autoRefresh > 0 {
+			m.scheduleRefresh(key, fn, opts)
+		}
+	}
+}
+
+// put caches value under key with the expiration resolved from opts. With a stale window or
+// a stale-on-error retention, the entry is kept in the store for the longer of the two beyond
+// its expiration.
+func (m *Memoizer[T]) put(key string, value T, opts callOptions[T]) {
+	f, expiration := m.freshness(value, opts)
+	m.store.Set(key, value, expiration)
+	m.access.stored(key, time.Now(), f)
+	m.checksum(key, value)
+	m.setTags(key, opts.tags)
+	m.errors.remove(key)
+}
+
+// freshness resolves the freshness of value stored now, and how long the store retains it.
+func (m *Memoizer[T]) freshness(value T, opts callOptions[T]) (f freshness, expiration time.Duration) {
+	expiration = m.expiration(value, opts)
+	if expiration > 0 {
+		f.dueAt = time.Now().Add(expiration)
+	}
+	if expiration > 0 && (opts.staleFor > 0 || opts.staleOnErrorFor > 0) {
+		f.staleAt = time.Now().Add(expiration)
+		f.expiresAt = f.staleAt.Add(opts.staleFor)
+		retain := opts.staleFor
+		if opts.staleOnErrorFor > retain {
+			retain = opts.staleOnErrorFor
+		}
+		expiration += retain
+	}
+	return f, expiration
+}
+
+// slide restarts the expiration of the entry of key, which holds value, as if it were stored
+// now, while keeping its access statistics and tags.
+func (m *Memoizer[T]) slide(key string, value T, opts callOptions[T]) {
+	f, expiration := m.freshness(value, opts)
+	if expiration <= 0 {
+		return
+	}
+	m.store.Set(key, value, expiration)
+	m.access.refreshed(key, f)
+}
+
+// call runs fn. If fn panics and a panic fallback is configured, call runs the fallback
+// instead and reports that it did; if the fallback fails as well, the original panic
+// propagates.
+func (m *Memoizer[T]) call(fn func() (T, error), opts callOptions[T]) (T, error, bool) {
+	if opts.panicFallback == nil {
+		res, err := fn()
+		return res, err, false
+	}
+
+	res, err, p := callRecovering(fn)
+	if p == nil {
+		return res, err, false
+	}
+	res, err, fallbackPanic := callRecovering(opts.panicFallback)
+	if fallbackPanic != nil || err != nil {
+		panic(p)
+	}
+	return res, nil, true
+}
+
+// callRecovering runs fn and captures a panic instead of propagating it.
+func callRecovering[T any](fn func() (T, error)) (res T, err error, p *recoveredPanic) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if p, ok = r.(*recoveredPanic); !ok {
+				p = &recoveredPanic{value: r, stack: debug.Stack()}
+			}
+		}
+	}()
+	res, err = fn()
+	return res, err, nil
+}
 
-	if err != nil && result == nil {
+// expiration resolves the expiration of a freshly computed result: the Memoizer's default,
+// unless an expiration callback overrides it, randomized by any TTL jitter, and clamped to the
+// configured TTL bounds.
+func (m *Memoizer[T]) expiration(res T, opts callOptions[T]) time.Duration {
+	expiration := m.defaultExpiration
+	if opts.expiration != nil {
+		expiration = opts.expiration(res)
+	}
+
+	// A callback returning zero is degenerate and subject to the floor; otherwise zero
+	// stands for the default expiration.
+	if expiration == 0 && (opts.expiration == nil || opts.minTTL <= 0) {
+		expiration = m.defaultExpiration
+	}
+	if opts.ttlJitter > 0 && expiration > 0 {
+		expiration = jitter(expiration, opts.ttlJitter)
+	}
+	if opts.minTTL > 0 && expiration < opts.minTTL && expiration != NoExpiration {
+		expiration = opts.minTTL
+	}
+	if opts.minRecompute > 0 && expiration > 0 && expiration < opts.minRecompute {
+		expiration = opts.minRecompute
+	}
+	if opts.maxTTL > 0 && (expiration < 0 || expiration > opts.maxTTL) {
+		expiration = opts.maxTTL
+	}
+	return expiration
+}
+
+// result converts the outcome of a singleflight call back to T. Whether the call failed is
+// decided by err alone; a nil result, which an interface-typed T may legitimately produce,
+// simply converts to the zero value. Each caller gets its own clone with WithClone.
+func (m *Memoizer[T]) result(result interface{}, err error) (T, error) {
+	if result == nil {
 		var zero T
 		return zero, err
 	}
 
-	return result.(T), err
+	return m.clone(result.(T)), err
+}
+
+// MemoizeContext is like Memoize, but passes ctx to fn and stops waiting when ctx is done,
+// returning ctx.Err(). Cancelling one caller does not stop a computation that other callers
+// are waiting on, unless the computation itself observes the cancellation: when several
+// callers share an in-flight computation, fn runs with the context of the caller that started
+// it. Use WithDetachedContext to shield the shared computation from that caller's cancellation
+// and deadline.
+//
+// The context passed to fn also records that key is being computed, so that a call of
+// MemoizeContext with that context that requests key again fails with ErrReentrantComputation
+// even when fn runs on a goroutine other than the caller's, such as with WithTimeout or
+// WithHedging, where the tracking of Memoize by goroutine cannot see the recursion.
+func (m *Memoizer[T]) MemoizeContext(ctx context.Context, key string, fn func(ctx context.Context) (T, error), options ...Option) (T, error) {
+	opts := m.resolve(options)
+	if opts.contextKeyAugmenter != nil {
+		key = opts.contextKeyAugmenter(ctx, key)
+	}
+
+	eager := opts.eagerBackgroundFill && !opts.forceRefresh
+	fnCtx := ctx
+	if opts.detachedContext || eager {
+		// A background fill outlives the call, so it must not be cancelled when the call returns.
+		fnCtx = context.WithoutCancel(ctx)
+	}
+	hashedKey := m.hashKey(key)
+	fnCtx = withComputing(fnCtx, m, hashedKey)
+	compute := func() (T, error) {
+		if m.reentrancy.maxDepth > 0 && computingFrom(ctx).depth(m) >= m.reentrancy.maxDepth {
+			var zero T
+			return zero, ErrMaxRecursionDepth
+		}
+		return fn(fnCtx)
+	}
+	if eager {
+		// The call returns without waiting, so there is nothing to cancel.
+		return m.Memoize(key, compute, options...)
+	}
+
+	if err := ctx.Err(); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	select {
+	case r := <-m.doChan(ctx, hashedKey, compute, opts):
+		return r.Value, repanic(r.Err, opts)
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// typed asserts that a value read from the cache is of type T. If it is not, the flexible
+// decoder is consulted when one is configured; otherwise the value is treated as a miss, so
+// that it is recomputed and overwritten, or typed panics with WithStrictTypes. The value is cloned
+// with WithClone, if configured.
+func (m *Memoizer[T]) typed(value interface{}) (T, bool) {
+	typedValue, ok := value.(T)
+	if ok {
+		return m.clone(typedValue), true
+	}
+	if value == nil && interface{}(typedValue) == nil {
+		// T is an interface type and a nil result was cached.
+		return typedValue, true
+	}
+	if m.decode != nil {
+		if decoded, ok := m.decode(value); ok {
+			return m.clone(decoded), true
+		}
+		return typedValue, false
+	}
+	if m.strictTypes {
+		panic(fmt.Errorf("%w: cached value of type %T", ErrTypeMismatch, value))
+	}
+	if m.logger != nil {
+		m.logger.Warn("memoizer cached value type mismatch", "type", fmt.Sprintf("%T", value))
+	}
+	return typedValue, false
 }
@@ -18,6 +18,7 @@ func TestMemoizer(t *testing.T) {
 	t.Run("Panic Propagation", testPanicPropagation)
 	t.Run("No Memoization on Error", testNoMemoizationOnError)
 	t.Run("No Memoization on Panic", testNoMemoizationOnPanic)
+	t.Run("Nil Results", testNilResults)
 }
 
 func testBasicFunctionality(t *testing.T) {
@@ -184,6 +185,42 @@ func testNoMemoizationOnPanic(t *testing.T) {
 	assert.Equal(t, 3, callCount, "Cached value should be used")
 }
 
+func testNilResults(t *testing.T) {
+	memoizer := NewMemoizer[any]()
+	callCount := 0
+
+	// (nil, nil) is a valid result and is cached.
+	result, err := memoizer.Memoize("nil_key", func() (any, error) {
+		callCount++
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	result, err = memoizer.Memoize("nil_key", func() (any, error) {
+		callCount++
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result, "the cached nil result should be used")
+	assert.Equal(t, 1, callCount)
+
+	// (nil, err) is an error and is not cached.
+	_, err = memoizer.Memoize("error_key", func() (any, error) {
+		callCount++
+		return nil, errors.New("intentional error")
+	})
+	require.Error(t, err)
+
+	result, err = memoizer.Memoize("error_key", func() (any, error) {
+		callCount++
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, 3, callCount, "the function should be called again after an error")
+}
+
 func TestMemoizerWithCustomExpiration(t *testing.T) {
 	memoizer := NewMemoizer[int]()
 	key := "custom_expiration_key"
@@ -253,3 +290,10 @@ func TestMemoizerWithCustomExpiration(t *testing.T) {
 	assert.Equal(t, 44, result5)
 	assert.Equal(t, 3, callCount, "Function should be called again after expiration")
 }
+
+func TestDefaultExpiration(t *testing.T) {
+	assert.Equal(t, NoExpiration, NewMemoizer[int]().DefaultExpiration())
+	assert.Equal(t, time.Minute, NewMemoizerWithCacheExpiration[int](time.Minute).DefaultExpiration())
+	assert.Equal(t, NoExpiration, NewMemoizerWithCacheExpiration[int](0).DefaultExpiration())
+	assert.Equal(t, NoExpiration, NewMemoizerWithOptions[int]().DefaultExpiration())
+}
@@ -0,0 +1,220 @@
+// Package memopeer deduplicates computations across a cluster of processes, in the style of
+// groupcache. Every key is owned by one peer, chosen by consistent hashing; a process that
+// misses a key it does not own fetches it from the owner over HTTP, so that the owner computes
+// each key once for the whole cluster:
+//
+//	pool := memopeer.NewPool("http://10.0.0.1:8080", users, loadUser)
+//	pool.Set("http://10.0.0.1:8080", "http://10.0.0.2:8080", "http://10.0.0.3:8080")
+//	http.Handle(memopeer.DefaultBasePath, pool)
+//	user, err := pool.Get(ctx, "42")
+//
+// Values fetched from other peers are also cached by the Memoizer of the process that fetched
+// them, for its default expiration, so that hot keys are not fetched on every call.
+package memopeer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/KevinWang15/memoizer"
+)
+
+// DefaultBasePath is the path under which a Pool serves the keys it owns, unless configured
+// otherwise with WithBasePath.
+const DefaultBasePath = "/_memoizer/"
+
+// defaultReplicas is how many times each peer is placed on the ring, unless configured
+// otherwise with WithReplicas.
+const defaultReplicas = 50
+
+// Pool routes the computation of each key to the peer that owns it. It is an http.Handler
+// that serves the keys the process owns to the other peers.
+type Pool[T any] struct {
+	self     string
+	basePath string
+	replicas int
+	memoizer *memoizer.Memoizer[T]
+	load     func(ctx context.Context, key string) (T, error)
+	codec    memoizer.Codec
+	client   *http.Client
+
+	mu   sync.RWMutex
+	ring *Ring
+}
+
+// Option configures a Pool.
+type Option[T any] func(p *Pool[T])
+
+// WithBasePath replaces DefaultBasePath, the path under which peers serve their keys. Every peer
+// of the cluster must use the same path.
+func WithBasePath[T any](basePath string) Option[T] {
+	return func(p *Pool[T]) {
+		p.basePath = basePath
+	}
+}
+
+// WithCodec replaces memoizer.JSONCodec, the Codec used to transfer values between peers. Every
+// peer of the cluster must use the same Codec.
+func WithCodec[T any](codec memoizer.Codec) Option[T] {
+	return func(p *Pool[T]) {
+		p.codec = codec
+	}
+}
+
+// WithHTTPClient replaces http.DefaultClient, the client used to fetch keys from other peers.
+func WithHTTPClient[T any](client *http.Client) Option[T] {
+	return func(p *Pool[T]) {
+		p.client = client
+	}
+}
+
+// WithReplicas sets how many times each peer is placed on the consistent hash ring; more
+// replicas spread keys more evenly. Every peer of the cluster must use the same number.
+func WithReplicas[T any](replicas int) Option[T] {
+	return func(p *Pool[T]) {
+		p.replicas = replicas
+	}
+}
+
+// NewPool creates and returns a new Pool for the process reachable by other peers at self, a
+// base URL such as "http://10.0.0.1:8080". Values are cached by m, and computed by load for the
+// keys the process owns. Until Set is called, the process owns every key.
+func NewPool[T any](self string, m *memoizer.Memoizer[T], load func(ctx context.Context, key string) (T, error), options ...Option[T]) *Pool[T] {
+	p := &Pool[T]{
+		self:     self,
+		basePath: DefaultBasePath,
+		replicas: defaultReplicas,
+		memoizer: m,
+		load:     load,
+		codec:    memoizer.JSONCodec{},
+		client:   http.DefaultClient,
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Set replaces the peers of the cluster, each identified by its base URL. It should include the
+// process itself.
+func (p *Pool[T]) Set(peers ...string) {
+	ring := NewRing(p.replicas)
+	ring.Add(peers...)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ring = ring
+}
+
+// Owner returns the base URL of the peer that owns key.
+func (p *Pool[T]) Owner(key string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.ring == nil {
+		return p.self
+	}
+	if owner := p.ring.Owner(key); owner != "" {
+		return owner
+	}
+	return p.self
+}
+
+// Get returns the value of key, from the local cache, or else from the peer that owns it, which
+// computes it if it is not cached there. If the owner cannot be reached, the value is computed
+// locally instead; errors returned by the owner's computation are returned as a *RemoteError.
+func (p *Pool[T]) Get(ctx context.Context, key string) (T, error) {
+	owner := p.Owner(key)
+	if owner == p.self {
+		return p.memoizer.MemoizeContext(ctx, key, p.loader(key))
+	}
+	return p.memoizer.MemoizeContext(ctx, key, func(ctx context.Context) (T, error) {
+		value, err := p.fetch(ctx, owner, key)
+		var remote *RemoteError
+		if err != nil && !errors.As(err, &remote) {
+			return p.load(ctx, key)
+		}
+		return value, err
+	})
+}
+
+// loader returns the computation of key by the process itself.
+func (p *Pool[T]) loader(key string) func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		return p.load(ctx, key)
+	}
+}
+
+// RemoteError is returned by Get when the computation of a key failed on the peer that owns it.
+type RemoteError struct {
+	Peer    string
+	Message string
+}
+
+// Error implements the error interface.
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("memopeer: computation failed on %s: %s", e.Peer, e.Message)
+}
+
+// fetch requests the value of key from peer.
+func (p *Pool[T]) fetch(ctx context.Context, peer, key string) (T, error) {
+	var value T
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(peer, "/")+p.basePath+url.PathEscape(key), nil)
+	if err != nil {
+		return value, fmt.Errorf("memopeer: fetch %q from %s: %w", key, peer, err)
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return value, fmt.Errorf("memopeer: fetch %q from %s: %w", key, peer, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return value, fmt.Errorf("memopeer: fetch %q from %s: %w", key, peer, err)
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnprocessableEntity:
+		return value, &RemoteError{Peer: peer, Message: strings.TrimSpace(string(body))}
+	default:
+		return value, fmt.Errorf("memopeer: fetch %q from %s: %s", key, peer, res.Status)
+	}
+	if err := p.codec.Unmarshal(body, &value); err != nil {
+		return value, fmt.Errorf("memopeer: decode %q from %s: %w", key, peer, err)
+	}
+	return value, nil
+}
+
+// ServeHTTP implements http.Handler. It serves the value of the requested key, computing it if
+// it is not cached. Keys are computed locally even if the process does not own them by its own
+// view of the cluster, so that peers with different views of the cluster never forward requests
+// in a loop.
+func (p *Pool[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.EscapedPath(), p.basePath) {
+		http.NotFound(w, r)
+		return
+	}
+	key, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), p.basePath))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	value, err := p.memoizer.MemoizeContext(r.Context(), key, p.loader(key))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	data, err := p.codec.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(data)
+}
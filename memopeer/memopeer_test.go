@@ -0,0 +1,94 @@
+package memopeer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cluster starts n peers that load keys by counting the computations of each peer.
+func cluster(t *testing.T, n int, load func(ctx context.Context, key string) (string, error)) ([]*Pool[string], []*atomic.Int32) {
+	pools := make([]*Pool[string], n)
+	calls := make([]*atomic.Int32, n)
+	urls := make([]string, n)
+	for i := range pools {
+		i := i
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pools[i].ServeHTTP(w, r)
+		}))
+		t.Cleanup(server.Close)
+		urls[i] = server.URL
+		calls[i] = &atomic.Int32{}
+		pools[i] = NewPool(server.URL, memoizer.NewMemoizer[string](), func(ctx context.Context, key string) (string, error) {
+			calls[i].Add(1)
+			return load(ctx, key)
+		})
+	}
+	for _, pool := range pools {
+		pool.Set(urls...)
+	}
+	return pools, calls
+}
+
+func TestPoolComputesEachKeyOnItsOwner(t *testing.T) {
+	pools, calls := cluster(t, 3, func(ctx context.Context, key string) (string, error) {
+		return "value of " + key, nil
+	})
+
+	for _, key := range []string{"a", "b", "c", "d", "e", "f/with slash"} {
+		for _, pool := range pools {
+			value, err := pool.Get(context.Background(), key)
+			require.NoError(t, err)
+			assert.Equal(t, "value of "+key, value)
+		}
+	}
+
+	var total int32
+	for i, pool := range pools {
+		total += calls[i].Load()
+		for _, key := range []string{"a", "b", "c"} {
+			if pool.Owner(key) == pool.self {
+				assert.NotZero(t, calls[i].Load())
+			}
+		}
+	}
+	assert.EqualValues(t, 6, total)
+}
+
+func TestPoolReturnsRemoteErrors(t *testing.T) {
+	pools, calls := cluster(t, 2, func(ctx context.Context, key string) (string, error) {
+		return "", errors.New("unavailable")
+	})
+	key := "a"
+	requester := pools[0]
+	if requester.Owner(key) == requester.self {
+		requester = pools[1]
+	}
+
+	_, err := requester.Get(context.Background(), key)
+	var remote *RemoteError
+	require.ErrorAs(t, err, &remote)
+	assert.Equal(t, "unavailable", remote.Message)
+	assert.EqualValues(t, 1, calls[0].Load()+calls[1].Load())
+}
+
+func TestPoolComputesLocallyWhenOwnerIsUnreachable(t *testing.T) {
+	var calls atomic.Int32
+	pool := NewPool("http://self", memoizer.NewMemoizer[string](), func(ctx context.Context, key string) (string, error) {
+		calls.Add(1)
+		return "local", nil
+	})
+	pool.Set("http://127.0.0.1:1")
+
+	value, err := pool.Get(context.Background(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, "local", value)
+	assert.EqualValues(t, 1, calls.Load())
+}
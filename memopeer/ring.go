@@ -0,0 +1,54 @@
+package memopeer
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// Ring is a consistent hash ring that assigns keys to peers. Each peer is placed on the ring
+// several times, so that keys spread evenly and adding or removing a peer only moves the keys
+// it gains or loses. A Ring is not safe for concurrent modification; Pool replaces its Ring
+// instead of modifying it.
+type Ring struct {
+	replicas int
+	hashes   []uint32
+	owners   map[uint32]string
+}
+
+// NewRing creates and returns a new, empty Ring that places each peer replicas times.
+func NewRing(replicas int) *Ring {
+	if replicas <= 0 {
+		replicas = 1
+	}
+	return &Ring{replicas: replicas, owners: make(map[uint32]string)}
+}
+
+// Add places peers on the ring.
+func (r *Ring) Add(peers ...string) {
+	for _, peer := range peers {
+		for i := 0; i < r.replicas; i++ {
+			hash := crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + peer))
+			r.hashes = append(r.hashes, hash)
+			r.owners[hash] = peer
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool {
+		return r.hashes[i] < r.hashes[j]
+	})
+}
+
+// Owner returns the peer that owns key, or an empty string if the ring is empty.
+func (r *Ring) Owner(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	hash := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= hash
+	})
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.owners[r.hashes[i]]
+}
@@ -0,0 +1,33 @@
+package memopeer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingSpreadsKeysAndMovesFewOnChange(t *testing.T) {
+	ring := NewRing(50)
+	assert.Empty(t, ring.Owner("key"))
+	ring.Add("a", "b", "c")
+
+	owners := make(map[string]string)
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprint(i)
+		owners[key] = ring.Owner(key)
+		counts[owners[key]]++
+	}
+	for _, peer := range []string{"a", "b", "c"} {
+		assert.Greater(t, counts[peer], 500, peer)
+	}
+
+	grown := NewRing(50)
+	grown.Add("a", "b", "c", "d")
+	for key, owner := range owners {
+		if moved := grown.Owner(key); moved != owner {
+			assert.Equal(t, "d", moved, "key %s moved between existing peers", key)
+		}
+	}
+}
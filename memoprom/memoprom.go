@@ -0,0 +1,147 @@
+// Package memoprom exports the statistics of a memoizer.Memoizer as Prometheus metrics.
+//
+// A Collector reports the hit and miss counts, hit ratio, evictions, entry count and in-flight
+// computations of a Memoizer, taken from its Stats, along with a histogram of computation
+// latencies and a count of failed computations, observed through WithComputeObserver. Every
+// metric carries a "name" label identifying the Memoizer, so that several Memoizers can be
+// registered side by side:
+//
+//	collector := memoprom.NewCollector("users")
+//	users := memoizer.New[User](collector.Observer())
+//	collector.Track(users)
+//	prometheus.MustRegister(collector)
+package memoprom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatsSource is implemented by memoizer.Memoizer. For a KeyedMemoizer, track its Memoizer.
+type StatsSource interface {
+	Stats() memoizer.Stats
+}
+
+// Collector is a prometheus.Collector for one Memoizer.
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	mu     sync.Mutex
+	source StatsSource
+
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	hitRatio  *prometheus.Desc
+	entries   *prometheus.Desc
+	inFlight  *prometheus.Desc
+	duration  prometheus.Histogram
+	errors    prometheus.Counter
+}
+
+// Option configures a Collector.
+type Option func(c *Collector)
+
+// WithNamespace replaces the default "memoizer" prefix of the metric names.
+func WithNamespace(namespace string) Option {
+	return func(c *Collector) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets replaces the default buckets of the computation latency histogram, which are
+// prometheus.DefBuckets, in seconds.
+func WithBuckets(buckets []float64) Option {
+	return func(c *Collector) {
+		c.buckets = buckets
+	}
+}
+
+// NewCollector creates and returns a new Collector whose metrics are labeled with name.
+func NewCollector(name string, options ...Option) *Collector {
+	c := &Collector{namespace: "memoizer", buckets: prometheus.DefBuckets}
+	for _, option := range options {
+		option(c)
+	}
+
+	labels := prometheus.Labels{"name": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(c.namespace, "", metric), help, nil, labels)
+	}
+	c.hits = desc("hits_total", "Number of lookups served from the cache.")
+	c.misses = desc("misses_total", "Number of lookups not served from the cache.")
+	c.evictions = desc("evictions_total", "Number of entries removed by the store through expiry or to make room.")
+	c.hitRatio = desc("hit_ratio", "Fraction of lookups served from the cache.")
+	c.entries = desc("entries", "Number of cached entries.")
+	c.inFlight = desc("in_flight_computations", "Number of computations currently running.")
+	c.duration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace:   c.namespace,
+		Name:        "computation_duration_seconds",
+		Help:        "Duration of computations of memoized functions.",
+		Buckets:     c.buckets,
+		ConstLabels: labels,
+	})
+	c.errors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   c.namespace,
+		Name:        "computation_errors_total",
+		Help:        "Number of computations of memoized functions that returned an error.",
+		ConstLabels: labels,
+	})
+	return c
+}
+
+// Observer returns a constructor option for memoizer.New that reports the
+// Memoizer's computations to the latency histogram and error count.
+func (c *Collector) Observer() memoizer.Option {
+	return memoizer.WithComputeObserver(c.observe)
+}
+
+// Track sets the Memoizer whose Stats are reported. Until it is called, only the computation
+// metrics are collected.
+func (c *Collector) Track(source StatsSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.source = source
+}
+
+// observe records a computation.
+func (c *Collector) observe(_ string, duration time.Duration, err error) {
+	c.duration.Observe(duration.Seconds())
+	if err != nil {
+		c.errors.Inc()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.hitRatio
+	ch <- c.entries
+	ch <- c.inFlight
+	c.duration.Describe(ch)
+	c.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	source := c.source
+	c.mu.Unlock()
+	if source != nil {
+		stats := source.Stats()
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions))
+		ch <- prometheus.MustNewConstMetric(c.hitRatio, prometheus.GaugeValue, stats.HitRate())
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Entries))
+		ch <- prometheus.MustNewConstMetric(c.inFlight, prometheus.GaugeValue, float64(stats.InFlight))
+	}
+	c.duration.Collect(ch)
+	c.errors.Collect(ch)
+}
@@ -0,0 +1,71 @@
+package memoprom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector("users")
+	m := memoizer.NewMemoizerWithOptions[int](collector.Observer())
+	collector.Track(m)
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	for _, key := range []string{"a", "a", "a", "b"} {
+		_, err := m.Memoize(key, func() (int, error) { return 1, nil })
+		require.NoError(t, err)
+	}
+	_, err := m.Memoize("c", func() (int, error) { return 0, errors.New("failed") })
+	require.Error(t, err)
+
+	expected := `
+# HELP memoizer_computation_errors_total Number of computations of memoized functions that returned an error.
+# TYPE memoizer_computation_errors_total counter
+memoizer_computation_errors_total{name="users"} 1
+# HELP memoizer_entries Number of cached entries.
+# TYPE memoizer_entries gauge
+memoizer_entries{name="users"} 2
+# HELP memoizer_hit_ratio Fraction of lookups served from the cache.
+# TYPE memoizer_hit_ratio gauge
+memoizer_hit_ratio{name="users"} 0.4
+# HELP memoizer_hits_total Number of lookups served from the cache.
+# TYPE memoizer_hits_total counter
+memoizer_hits_total{name="users"} 2
+# HELP memoizer_misses_total Number of lookups not served from the cache.
+# TYPE memoizer_misses_total counter
+memoizer_misses_total{name="users"} 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"memoizer_computation_errors_total", "memoizer_entries", "memoizer_hit_ratio", "memoizer_hits_total", "memoizer_misses_total"))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "memoizer_computation_duration_seconds" {
+			assert.Equal(t, uint64(3), family.GetMetric()[0].GetHistogram().GetSampleCount())
+		}
+	}
+}
+
+func TestCollectorWithNamespace(t *testing.T) {
+	collector := NewCollector("orders", WithNamespace("app_cache"), WithBuckets([]float64{0.1, 1}))
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	// Without a tracked Memoizer, only the computation metrics are collected.
+	count, err := testutil.GatherAndCount(registry)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	count, err = testutil.GatherAndCount(registry, "app_cache_computation_errors_total")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,94 @@
+package memoredis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/redis/go-redis/v9"
+)
+
+// Broadcaster is a memoizer.Broadcaster that carries invalidations over a Redis pub/sub
+// channel, so that Memoizers sharing a Redis-backed TieredStore drop each other's stale local
+// entries.
+type Broadcaster struct {
+	client  redis.UniversalClient
+	channel string
+	onError func(err error)
+
+	mu     sync.Mutex
+	pubsub *redis.PubSub
+	done   chan struct{}
+}
+
+// NewBroadcaster creates and returns a new Broadcaster that publishes to and subscribes on
+// channel. onError, if not nil, is called with every Redis or decoding error of the
+// subscription.
+func NewBroadcaster(client redis.UniversalClient, channel string, onError func(err error)) *Broadcaster {
+	return &Broadcaster{client: client, channel: channel, onError: onError}
+}
+
+// Publish implements memoizer.Broadcaster.
+func (b *Broadcaster) Publish(inv memoizer.Invalidation) error {
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return fmt.Errorf("memoredis: encode invalidation: %w", err)
+	}
+	if err := b.client.Publish(context.Background(), b.channel, data).Err(); err != nil {
+		return fmt.Errorf("memoredis: publish invalidation: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements memoizer.Broadcaster. It waits for the subscription to be confirmed, so
+// that invalidations published once it returns are received, and then delivers them until
+// Close is called. If Redis cannot be reached, the error is reported and the subscription is
+// retried in the background. Subscribing again replaces the previous subscription.
+func (b *Broadcaster) Subscribe(fn func(inv memoizer.Invalidation)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeLocked()
+
+	pubsub := b.client.Subscribe(context.Background(), b.channel)
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		b.error(fmt.Errorf("memoredis: subscribe: %w", err))
+	}
+	done := make(chan struct{})
+	b.pubsub, b.done = pubsub, done
+	go func() {
+		defer close(done)
+		for message := range pubsub.Channel() {
+			var inv memoizer.Invalidation
+			if err := json.Unmarshal([]byte(message.Payload), &inv); err != nil {
+				b.error(fmt.Errorf("memoredis: decode invalidation: %w", err))
+				continue
+			}
+			fn(inv)
+		}
+	}()
+}
+
+// Close ends the subscription.
+func (b *Broadcaster) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closeLocked()
+}
+
+func (b *Broadcaster) closeLocked() error {
+	if b.pubsub == nil {
+		return nil
+	}
+	err := b.pubsub.Close()
+	<-b.done
+	b.pubsub, b.done = nil, nil
+	return err
+}
+
+func (b *Broadcaster) error(err error) {
+	if b.onError != nil {
+		b.onError(err)
+	}
+}
@@ -0,0 +1,65 @@
+package memoredis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcasterInvalidatesLocalTiers(t *testing.T) {
+	_, client := newClient(t)
+	newInstance := func() (*memoizer.Memoizer[user], *memoizer.TieredStore) {
+		broadcaster := NewBroadcaster(client, "invalidations", func(err error) {
+			t.Error(err)
+		})
+		t.Cleanup(func() {
+			_ = broadcaster.Close()
+		})
+		store := memoizer.NewTieredStore(memoizer.NewGoCacheStore(0), New[user](client), time.Minute)
+		return memoizer.NewMemoizerWithOptions[user](memoizer.WithStore(store), memoizer.WithBroadcaster(broadcaster)), store
+	}
+	first, _ := newInstance()
+	second, secondStore := newInstance()
+
+	first.Set("1", user{Name: "alice"}, time.Hour)
+	value, ok := second.Peek("1")
+	require.True(t, ok)
+	assert.Equal(t, user{Name: "alice"}, value)
+
+	// Replacing the value on the first instance leaves the second serving its local copy until
+	// the invalidation arrives.
+	first.Delete("1")
+	first.Set("1", user{Name: "alice", Admin: true}, time.Hour)
+	assert.Eventually(t, func() bool {
+		_, ok := secondStore.Local().Get("1")
+		return !ok
+	}, time.Second, 5*time.Millisecond)
+
+	value, ok = second.Peek("1")
+	require.True(t, ok)
+	assert.Equal(t, user{Name: "alice", Admin: true}, value)
+}
+
+func TestBroadcasterReportsDecodeErrors(t *testing.T) {
+	_, client := newClient(t)
+	errs := make(chan error, 1)
+	broadcaster := NewBroadcaster(client, "invalidations", func(err error) {
+		errs <- err
+	})
+	defer broadcaster.Close()
+	broadcaster.Subscribe(func(memoizer.Invalidation) {
+		t.Error("a malformed message should not be delivered")
+	})
+
+	require.NoError(t, client.Publish(context.Background(), "invalidations", "not json").Err())
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "decode invalidation")
+	case <-time.After(time.Second):
+		t.Fatal("the decode error was not reported")
+	}
+}
@@ -0,0 +1,63 @@
+package memoredis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// unlockScript deletes a lock only if it is still held by the token that acquired it, so that a
+// holder whose lock expired does not release the lock of the process that acquired it next.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Locker is a memoizer.Locker that acquires locks with SET NX, so that a fleet of Memoizers
+// sharing a Redis-backed store computes each missing key once.
+type Locker struct {
+	client  redis.UniversalClient
+	prefix  string
+	onError func(err error)
+}
+
+// NewLocker creates and returns a new Locker whose locks are Redis keys made of prefix followed
+// by the cache key. onError, if not nil, is called with every error releasing a lock.
+func NewLocker(client redis.UniversalClient, prefix string, onError func(err error)) *Locker {
+	return &Locker{client: client, prefix: prefix, onError: onError}
+}
+
+// TryLock implements memoizer.Locker.
+func (l *Locker) TryLock(key string, ttl time.Duration) (unlock func(), acquired bool, err error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, false, fmt.Errorf("memoredis: generate lock token: %w", err)
+	}
+	value := hex.EncodeToString(token)
+
+	acquired, err = l.client.SetNX(context.Background(), l.prefix+key, value, ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("memoredis: acquire lock %q: %w", key, err)
+	}
+	if !acquired {
+		return nil, false, nil
+	}
+	return func() {
+		if err := unlockScript.Run(context.Background(), l.client, []string{l.prefix + key}, value).Err(); err != nil {
+			l.error(fmt.Errorf("memoredis: release lock %q: %w", key, err))
+		}
+	}, true, nil
+}
+
+// error reports err to the Locker's error handler, if any.
+func (l *Locker) error(err error) {
+	if l.onError != nil {
+		l.onError(err)
+	}
+}
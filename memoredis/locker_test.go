@@ -0,0 +1,49 @@
+package memoredis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockerExcludesOtherHolders(t *testing.T) {
+	server, client := newClient(t)
+	locker := NewLocker(client, "locks:", func(err error) {
+		t.Error(err)
+	})
+
+	unlock, acquired, err := locker.TryLock("key", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	assert.True(t, server.Exists("locks:key"))
+
+	_, acquired, err = locker.TryLock("key", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	unlock()
+	assert.False(t, server.Exists("locks:key"))
+	_, acquired, err = locker.TryLock("key", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestLockerUnlockKeepsLockOfNextHolder(t *testing.T) {
+	server, client := newClient(t)
+	locker := NewLocker(client, "locks:", nil)
+
+	unlock, acquired, err := locker.TryLock("key", time.Second)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	server.FastForward(2 * time.Second)
+
+	_, acquired, err = locker.TryLock("key", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// The first holder's lock expired, so releasing it must not release the second's.
+	unlock()
+	assert.True(t, server.Exists("locks:key"))
+}
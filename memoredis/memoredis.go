@@ -0,0 +1,249 @@
+// Package memoredis provides a Redis-backed memoizer.Store, so that memoized results can be
+// shared across processes and survive restarts.
+//
+// Values are serialized with a memoizer.Codec, JSON by default. Because a Store cannot return
+// errors, Redis and codec failures are reported to an optional error handler: a failed read is
+// treated as a cache miss, and a failed write leaves the value uncached.
+package memoredis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/redis/go-redis/v9"
+)
+
+// touchScript refreshes the expiration of a key, in milliseconds or removing it if not positive,
+// if the key holds data whose SHA-1 digest is the given one. It returns 1 if it did.
+var touchScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if not current or redis.sha1hex(current) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[2]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+else
+	redis.call("PERSIST", KEYS[1])
+end
+return 1
+`)
+
+// Store is a memoizer.Store that keeps values of type T in Redis.
+type Store[T any] struct {
+	client  redis.UniversalClient
+	prefix  string
+	codec   memoizer.Codec
+	onError func(err error)
+	timeout time.Duration
+	migrate func(raw []byte) (T, bool)
+	// skipUnchanged is set by WithSkipUnchangedWrites.
+	skipUnchanged bool
+}
+
+// Option configures a Store.
+type Option[T any] func(s *Store[T])
+
+// WithPrefix namespaces every key written by the Store, so that several Memoizers can share
+// one Redis database and Flush only removes the Store's own keys.
+func WithPrefix[T any](prefix string) Option[T] {
+	return func(s *Store[T]) {
+		s.prefix = prefix
+	}
+}
+
+// WithCodec replaces the default JSON serialization.
+func WithCodec[T any](codec memoizer.Codec) Option[T] {
+	return func(s *Store[T]) {
+		s.codec = codec
+	}
+}
+
+// WithDecodeMigrator registers a function that translates data the codec fails to decode, such
+// as values written in the format of a previous version of T, into a T. A migrated value is
+// written back in the current format, keeping its expiration, so that it is only migrated once;
+// data that migrate reports false for is treated as a miss, like any undecodable data. This
+// allows the shape of T to evolve without dropping or recomputing the cache.
+func WithDecodeMigrator[T any](migrate func(raw []byte) (T, bool)) Option[T] {
+	return func(s *Store[T]) {
+		s.migrate = migrate
+	}
+}
+
+// WithSkipUnchangedWrites avoids rewriting a value that Redis already holds, such as a mostly
+// stable value recomputed on every refresh. The Store still encodes the value, but first sends
+// Redis only its digest: if the stored data matches, just its expiration is refreshed, and the
+// value itself is neither transferred nor written. Otherwise it is written as usual, at the
+// cost of one more round trip.
+func WithSkipUnchangedWrites[T any]() Option[T] {
+	return func(s *Store[T]) {
+		s.skipUnchanged = true
+	}
+}
+
+// WithErrorHandler registers a function that is called with every Redis or codec error.
+func WithErrorHandler[T any](onError func(err error)) Option[T] {
+	return func(s *Store[T]) {
+		s.onError = onError
+	}
+}
+
+// WithTimeout bounds each Redis operation. By default, operations rely on the client's own
+// timeouts.
+func WithTimeout[T any](timeout time.Duration) Option[T] {
+	return func(s *Store[T]) {
+		s.timeout = timeout
+	}
+}
+
+// New creates and returns a new Store that uses client.
+func New[T any](client redis.UniversalClient, options ...Option[T]) *Store[T] {
+	s := &Store[T]{client: client, codec: memoizer.JSONCodec{}}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Get implements memoizer.Store.
+func (s *Store[T]) Get(key string) (interface{}, bool) {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			s.error(fmt.Errorf("memoredis: get %q: %w", key, err))
+		}
+		return nil, false
+	}
+	var value T
+	if err := s.codec.Unmarshal(data, &value); err != nil {
+		if value, ok := s.migrated(ctx, key, data); ok {
+			return value, true
+		}
+		s.error(fmt.Errorf("memoredis: decode %q: %w", key, err))
+		return nil, false
+	}
+	return value, true
+}
+
+// migrated translates data stored under key that the codec failed to decode with the migrator
+// of WithDecodeMigrator, if any, and writes the result back in the current format.
+func (s *Store[T]) migrated(ctx context.Context, key string, data []byte) (T, bool) {
+	if s.migrate == nil {
+		var zero T
+		return zero, false
+	}
+	value, ok := s.migrate(data)
+	if !ok {
+		return value, false
+	}
+	encoded, err := s.codec.Marshal(value)
+	if err != nil {
+		s.error(fmt.Errorf("memoredis: encode %q: %w", key, err))
+		return value, true
+	}
+	// Only replace the entry if it still exists, so that a concurrent Delete is not undone.
+	err = s.client.SetArgs(ctx, s.prefix+key, encoded, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		s.error(fmt.Errorf("memoredis: set %q: %w", key, err))
+	}
+	return value, true
+}
+
+// Set implements memoizer.Store. A negative ttl stores the value without expiration.
+func (s *Store[T]) Set(key string, value interface{}, ttl time.Duration) {
+	typed, ok := value.(T)
+	if !ok && value != nil {
+		s.error(fmt.Errorf("memoredis: set %q: unexpected value type %T", key, value))
+		return
+	}
+	data, err := s.codec.Marshal(typed)
+	if err != nil {
+		s.error(fmt.Errorf("memoredis: encode %q: %w", key, err))
+		return
+	}
+	if ttl < 0 {
+		ttl = 0 // go-redis treats zero as no expiration
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+	if s.skipUnchanged && s.touch(ctx, key, data, ttl) {
+		return
+	}
+	if err := s.client.Set(ctx, s.prefix+key, data, ttl).Err(); err != nil {
+		s.error(fmt.Errorf("memoredis: set %q: %w", key, err))
+	}
+}
+
+// touch refreshes the expiration of key to ttl, or removes it if ttl is zero, if key already
+// holds exactly data. It reports whether it did.
+func (s *Store[T]) touch(ctx context.Context, key string, data []byte, ttl time.Duration) bool {
+	digest := sha1.Sum(data)
+	milliseconds := ttl.Milliseconds()
+	if ttl > 0 && milliseconds == 0 {
+		milliseconds = 1
+	}
+	touched, err := touchScript.Run(ctx, s.client, []string{s.prefix + key}, hex.EncodeToString(digest[:]), milliseconds).Int()
+	if err != nil {
+		s.error(fmt.Errorf("memoredis: set %q: %w", key, err))
+		return false
+	}
+	return touched == 1
+}
+
+// Delete implements memoizer.Store.
+func (s *Store[T]) Delete(key string) {
+	ctx, cancel := s.context()
+	defer cancel()
+	if err := s.client.Del(ctx, s.prefix+key).Err(); err != nil {
+		s.error(fmt.Errorf("memoredis: delete %q: %w", key, err))
+	}
+}
+
+// Flush implements memoizer.Store. It removes every key that starts with the Store's prefix;
+// without a prefix, that is every key in the database.
+func (s *Store[T]) Flush() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	iter := s.client.Scan(ctx, 0, s.prefix+"*", 1000).Iterator()
+	batch := make([]string, 0, 1000)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.client.Del(ctx, batch...).Err(); err != nil {
+			s.error(fmt.Errorf("memoredis: flush: %w", err))
+		}
+		batch = batch[:0]
+	}
+	for iter.Next(ctx) {
+		if batch = append(batch, iter.Val()); len(batch) == cap(batch) {
+			flush()
+		}
+	}
+	flush()
+	if err := iter.Err(); err != nil {
+		s.error(fmt.Errorf("memoredis: flush: %w", err))
+	}
+}
+
+func (s *Store[T]) context() (context.Context, context.CancelFunc) {
+	if s.timeout > 0 {
+		return context.WithTimeout(context.Background(), s.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func (s *Store[T]) error(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
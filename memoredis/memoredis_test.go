@@ -0,0 +1,177 @@
+package memoredis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Name  string
+	Admin bool
+}
+
+func newClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return server, client
+}
+
+func TestStoreSharedAcrossMemoizers(t *testing.T) {
+	server, client := newClient(t)
+	first := memoizer.NewMemoizerWithOptions[user](memoizer.WithStore(New[user](client, WithPrefix[user]("users:"))))
+	second := memoizer.NewMemoizerWithOptions[user](memoizer.WithStore(New[user](client, WithPrefix[user]("users:"))))
+
+	result, err := first.Memoize("1", func() (user, error) {
+		return user{Name: "alice", Admin: true}, nil
+	}, memoizer.WithExpiration(func(interface{}) time.Duration {
+		return time.Minute
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, user{Name: "alice", Admin: true}, result)
+	assert.True(t, server.Exists("users:1"))
+	assert.Equal(t, time.Minute, server.TTL("users:1"))
+
+	// Another process sharing the database sees the cached value.
+	result, err = second.Memoize("1", func() (user, error) {
+		return user{}, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, user{Name: "alice", Admin: true}, result)
+
+	server.FastForward(2 * time.Minute)
+	_, ok := New[user](client, WithPrefix[user]("users:")).Get("1")
+	assert.False(t, ok, "the entry should expire in Redis")
+}
+
+func TestStoreFlushOnlyRemovesPrefixedKeys(t *testing.T) {
+	server, client := newClient(t)
+	store := New[int](client, WithPrefix[int]("memo:"))
+	store.Set("a", 1, memoizer.NoExpiration)
+	store.Set("b", 2, memoizer.NoExpiration)
+	require.NoError(t, server.Set("unrelated", "x"))
+
+	assert.Equal(t, time.Duration(0), server.TTL("memo:a"), "a negative TTL should not expire")
+
+	store.Flush()
+	assert.False(t, server.Exists("memo:a"))
+	assert.False(t, server.Exists("memo:b"))
+	assert.True(t, server.Exists("unrelated"))
+}
+
+func TestStoreErrors(t *testing.T) {
+	server, client := newClient(t)
+	var errs []error
+	store := New[int](client, WithErrorHandler[int](func(err error) {
+		errs = append(errs, err)
+	}))
+
+	// Undecodable data is reported and treated as a miss.
+	require.NoError(t, server.Set("corrupt", "not json"))
+	_, ok := store.Get("corrupt")
+	assert.False(t, ok)
+	require.Len(t, errs, 1)
+
+	// A missing key is not an error.
+	_, ok = store.Get("missing")
+	assert.False(t, ok)
+	assert.Len(t, errs, 1)
+
+	// Connection failures are reported and treated as misses.
+	server.Close()
+	_, ok = store.Get("key")
+	assert.False(t, ok)
+	assert.Len(t, errs, 2)
+}
+
+func TestStoreWithDecodeMigrator(t *testing.T) {
+	server, client := newClient(t)
+	var errs []error
+	store := New[user](client, WithDecodeMigrator(func(raw []byte) (user, bool) {
+		// The previous version of the cache stored bare names.
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return user{}, false
+		}
+		return user{Name: name}, true
+	}), WithErrorHandler[user](func(err error) {
+		errs = append(errs, err)
+	}))
+
+	require.NoError(t, server.Set("1", `"alice"`))
+	server.SetTTL("1", time.Minute)
+	value, ok := store.Get("1")
+	require.True(t, ok)
+	assert.Equal(t, user{Name: "alice"}, value)
+
+	// The migrated value is stored in the new format, keeping its expiration.
+	stored, err := server.Get("1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Name":"alice","Admin":false}`, stored)
+	assert.Equal(t, time.Minute, server.TTL("1"))
+
+	// Data the migrator rejects is a miss.
+	require.NoError(t, server.Set("2", "not json"))
+	_, ok = store.Get("2")
+	assert.False(t, ok)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "decode")
+}
+
+// setCounter is a redis.Hook that counts SET commands.
+type setCounter struct {
+	sets int
+}
+
+func (c *setCounter) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (c *setCounter) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if cmd.Name() == "set" {
+			c.sets++
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (c *setCounter) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestStoreWithSkipUnchangedWrites(t *testing.T) {
+	server, client := newClient(t)
+	counter := &setCounter{}
+	client.AddHook(counter)
+	store := New[user](client, WithSkipUnchangedWrites[user]())
+
+	store.Set("1", user{Name: "alice"}, time.Minute)
+	require.Equal(t, 1, counter.sets)
+
+	// An unchanged value is not written again, but its expiration is refreshed.
+	server.FastForward(30 * time.Second)
+	store.Set("1", user{Name: "alice"}, time.Minute)
+	assert.Equal(t, 1, counter.sets)
+	assert.Equal(t, time.Minute, server.TTL("1"))
+	store.Set("1", user{Name: "alice"}, memoizer.NoExpiration)
+	assert.Equal(t, 1, counter.sets)
+	assert.Equal(t, time.Duration(0), server.TTL("1"))
+
+	store.Set("1", user{Name: "alice", Admin: true}, time.Minute)
+	assert.Equal(t, 2, counter.sets)
+	value, ok := store.Get("1")
+	require.True(t, ok)
+	assert.Equal(t, user{Name: "alice", Admin: true}, value)
+}
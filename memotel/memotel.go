@@ -0,0 +1,59 @@
+// Package memotel instruments a memoizer.Memoizer with OpenTelemetry tracing.
+//
+// Each cache miss is recorded as a span covering the caller's wait for the computation, with
+// the key, whether the computation was shared with other callers, and its error. Each cache
+// hit is recorded as an event on the caller's current span. Spans are children of the span in
+// the context given to MemoizeContext; calls that take no context start root spans.
+//
+//	m := memoizer.New[User](memotel.WithTracer(otel.Tracer("users")))
+package memotel
+
+import (
+	"context"
+
+	"github.com/KevinWang15/memoizer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// SpanName is the name of the spans started for cache misses.
+	SpanName = "memoizer.miss"
+	// HitEventName is the name of the events recorded for cache hits.
+	HitEventName = "memoizer.hit"
+
+	// KeyAttribute holds the cache key of a hit or miss.
+	KeyAttribute = attribute.Key("memoizer.key")
+	// SharedAttribute reports whether a missed computation was shared with other callers.
+	SharedAttribute = attribute.Key("memoizer.shared")
+)
+
+// WithTracer returns a constructor option for memoizer.New that traces the
+// Memoizer's cache hits and misses with tracer.
+func WithTracer(tracer trace.Tracer) memoizer.Option {
+	return memoizer.WithCallTracer(&callTracer{tracer: tracer})
+}
+
+// callTracer is a memoizer.CallTracer that records spans with an OpenTelemetry tracer.
+type callTracer struct {
+	tracer trace.Tracer
+}
+
+// Hit implements memoizer.CallTracer.
+func (t *callTracer) Hit(ctx context.Context, key string) {
+	trace.SpanFromContext(ctx).AddEvent(HitEventName, trace.WithAttributes(KeyAttribute.String(key)))
+}
+
+// Miss implements memoizer.CallTracer.
+func (t *callTracer) Miss(ctx context.Context, key string) func(err error, shared bool) {
+	_, span := t.tracer.Start(ctx, SpanName, trace.WithAttributes(KeyAttribute.String(key)))
+	return func(err error, shared bool) {
+		span.SetAttributes(SharedAttribute.Bool(shared))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,52 @@
+package memotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/KevinWang15/memoizer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+	m := memoizer.NewMemoizerWithOptions[int](WithTracer(tracer))
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	_, err := m.MemoizeContext(ctx, "key", func(context.Context) (int, error) { return 1, nil })
+	require.NoError(t, err)
+	_, err = m.MemoizeContext(ctx, "key", func(context.Context) (int, error) { return 2, nil })
+	require.NoError(t, err)
+	parent.End()
+
+	_, err = m.Memoize("failing", func() (int, error) { return 0, errors.New("failed") })
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	miss := spans[0]
+	assert.Equal(t, SpanName, miss.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), miss.Parent().SpanID())
+	assert.Contains(t, miss.Attributes(), KeyAttribute.String("key"))
+	assert.Contains(t, miss.Attributes(), SharedAttribute.Bool(false))
+	assert.Equal(t, codes.Unset, miss.Status().Code)
+
+	request := spans[1]
+	require.Len(t, request.Events(), 1)
+	assert.Equal(t, HitEventName, request.Events()[0].Name)
+	assert.Equal(t, []attribute.KeyValue{KeyAttribute.String("key")}, request.Events()[0].Attributes)
+
+	failed := spans[2]
+	assert.False(t, failed.Parent().IsValid())
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.Equal(t, "failed", failed.Status().Description)
+}
@@ -0,0 +1,87 @@
+package memoizer
+
+import "runtime/debug"
+
+// MemoizeMulti runs fn, which produces several related values at once, and caches each
+// returned value under its own key, so that later lookups of any of those keys hit. For
+// example, a single API response may yield a user, their permissions and their settings.
+//
+// Concurrent calls with the same primaryKey share a single execution of fn. While every key
+// returned by the last execution is still cached, MemoizeMulti returns the cached values
+// without running fn again. On error nothing is cached. Expiration options apply to each
+// cached value; the returned map belongs to the caller.
+func (m *Memoizer[T]) MemoizeMulti(primaryKey string, fn func() (map[string]T, error), options ...Option) (map[string]T, error) {
+	opts := m.resolve(options)
+	if opts.invalid != nil {
+		return nil, opts.invalid
+	}
+	if !m.activity.enter() {
+		return nil, ErrClosed
+	}
+	defer m.activity.leave()
+
+	primaryKey = m.hashKey(primaryKey)
+	if values, ok := m.lookupMulti(primaryKey); ok {
+		return values, nil
+	}
+
+	result, err, _ := m.multiFlightGroup.Do(primaryKey, func() (result interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				result, err = nil, &recoveredPanic{value: r, stack: debug.Stack()}
+			}
+		}()
+
+		release := m.acquireComputation()
+		values, err := fn()
+		release()
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(values))
+		for key, value := range values {
+			m.put(m.hashKey(key), value, opts)
+			keys = append(keys, key)
+		}
+
+		m.mu.Lock()
+		if m.multiKeys == nil {
+			m.multiKeys = make(map[string][]string)
+		}
+		m.multiKeys[primaryKey] = keys
+		m.mu.Unlock()
+		return values, nil
+	})
+	if err = repanic(err, opts); err != nil {
+		return nil, err
+	}
+
+	// Callers sharing the computation each get their own copy of the map.
+	shared := result.(map[string]T)
+	values := make(map[string]T, len(shared))
+	for key, value := range shared {
+		values[key] = m.clone(value)
+	}
+	return values, nil
+}
+
+// lookupMulti returns the values produced by the last MemoizeMulti computation for
+// primaryKey, if all of them are still cached.
+func (m *Memoizer[T]) lookupMulti(primaryKey string) (map[string]T, bool) {
+	m.mu.Lock()
+	keys, ok := m.multiKeys[primaryKey]
+	m.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	values := make(map[string]T, len(keys))
+	for _, key := range keys {
+		value, ok := m.lookup(m.hashKey(key))
+		if !ok {
+			return nil, false
+		}
+		values[key] = value
+	}
+	return values, true
+}
@@ -0,0 +1,65 @@
+package memoizer
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizeMulti(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	var invocations int32
+	fn := func() (map[string]string, error) {
+		atomic.AddInt32(&invocations, 1)
+		return map[string]string{
+			"user:1":        "alice",
+			"permissions:1": "admin",
+			"settings:1":    "dark-mode",
+		}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values, err := memoizer.MemoizeMulti("profile:1", fn)
+			require.NoError(t, err)
+			assert.Len(t, values, 3)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&invocations))
+
+	// Every sub-key is individually retrievable.
+	for key, expected := range map[string]string{"user:1": "alice", "permissions:1": "admin", "settings:1": "dark-mode"} {
+		result, err := memoizer.Memoize(key, func() (string, error) {
+			return "", errors.New("this should not be called")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	}
+
+	// Once a sub-key is gone, the next call recomputes.
+	memoizer.store.Delete("settings:1")
+	_, err := memoizer.MemoizeMulti("profile:1", fn)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&invocations))
+}
+
+func TestMemoizeMultiError(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+
+	values, err := memoizer.MemoizeMulti("profile:1", func() (map[string]string, error) {
+		return map[string]string{"user:1": "alice"}, errors.New("intentional error")
+	})
+	require.Error(t, err)
+	assert.Nil(t, values)
+
+	_, cached := memoizer.store.Get("user:1")
+	assert.False(t, cached, "nothing should be cached on error")
+}
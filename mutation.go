@@ -0,0 +1,154 @@
+package memoizer
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// mutationDetector verifies that cached values are not mutated, as installed by
+// WithMutationDetection.
+type mutationDetector struct {
+	onMutation func(key string)
+}
+
+// checksum records the checksum of the value just stored for key.
+func (m *Memoizer[T]) checksum(key string, value interface{}) {
+	if m.mutations == nil {
+		return
+	}
+	m.access.checksummed(key, checksum(value))
+}
+
+// verify compares the value the store holds for key with its recorded checksum, and reports
+// a mismatch once.
+func (m *Memoizer[T]) verify(key string, value interface{}) {
+	if m.mutations == nil {
+		return
+	}
+	expected, ok := m.access.checksum(key)
+	if !ok {
+		return
+	}
+	sum := checksum(value)
+	if sum == expected {
+		return
+	}
+	// Record the new checksum so that each mutation is reported once.
+	m.access.checksummed(key, sum)
+	if m.logger != nil {
+		m.logger.Error("memoizer cached value was mutated", "key", key)
+	}
+	if m.mutations.onMutation != nil {
+		m.mutations.onMutation(key)
+		return
+	}
+	panic(fmt.Sprintf("memoizer: cached value of key %q was mutated", key))
+}
+
+// checksummed records the checksum of the value cached for key.
+func (a *accessTracker) checksummed(key string, sum uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if stat, ok := a.stats[key]; ok {
+		stat.checksum, stat.hasChecksum = sum, true
+		a.stats[key] = stat
+	}
+}
+
+// checksum returns the checksum recorded for the value cached for key, if any.
+func (a *accessTracker) checksum(key string) (uint64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stat := a.stats[key]
+	return stat.checksum, stat.hasChecksum
+}
+
+// checksum hashes the contents of value, following pointers, slices, maps and interfaces, and
+// including unexported fields.
+func checksum(value interface{}) uint64 {
+	h := fnv.New64a()
+	writeChecksum(h, reflect.ValueOf(value), make(map[uintptr]bool))
+	return h.Sum64()
+}
+
+// writeChecksum writes the contents of v to h. visited holds the pointers being hashed, so that
+// cycles are hashed once.
+func writeChecksum(h hash.Hash64, v reflect.Value, visited map[uintptr]bool) {
+	var buf [8]byte
+	writeUint := func(n uint64) {
+		for i := range buf {
+			buf[i] = byte(n >> (8 * i))
+		}
+		_, _ = h.Write(buf[:])
+	}
+
+	if !v.IsValid() {
+		writeUint(0)
+		return
+	}
+	writeUint(uint64(v.Kind()))
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(math.Float64bits(real(v.Complex())))
+		writeUint(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		_, _ = h.Write([]byte(v.String()))
+	case reflect.Pointer:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		if visited[v.Pointer()] {
+			writeUint(uint64(v.Pointer()))
+			return
+		}
+		visited[v.Pointer()] = true
+		writeChecksum(h, v.Elem(), visited)
+		delete(visited, v.Pointer())
+	case reflect.Interface:
+		if !v.IsNil() {
+			_, _ = h.Write([]byte(v.Elem().Type().String()))
+		}
+		writeChecksum(h, v.Elem(), visited)
+	case reflect.Slice, reflect.Array:
+		writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			writeChecksum(h, v.Index(i), visited)
+		}
+	case reflect.Map:
+		// Map iteration order is random, so entries are hashed separately and combined in an
+		// order-independent way.
+		writeUint(uint64(v.Len()))
+		var sum uint64
+		for iter := v.MapRange(); iter.Next(); {
+			entry := fnv.New64a()
+			writeChecksum(entry, iter.Key(), visited)
+			writeChecksum(entry, iter.Value(), visited)
+			sum += entry.Sum64()
+		}
+		writeUint(sum)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeChecksum(h, v.Field(i), visited)
+		}
+	default:
+		// Channels, functions and unsafe pointers are compared by identity.
+		writeUint(uint64(v.Pointer()))
+	}
+}
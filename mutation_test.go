@@ -0,0 +1,72 @@
+package memoizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profile struct {
+	Name   string
+	Scores map[string]int
+	Friend *profile
+	notes  []string
+}
+
+func TestMutationDetectionPanicsOnMutatedValues(t *testing.T) {
+	m := NewMemoizerWithOptions[[]int](WithMutationDetection())
+	fn := func() ([]int, error) {
+		return []int{1, 2, 3}, nil
+	}
+
+	value, err := m.Memoize("key", fn)
+	require.NoError(t, err)
+	_, err = m.Memoize("key", fn)
+	require.NoError(t, err, "unmodified values pass verification")
+
+	value[0] = 42
+	assert.PanicsWithValue(t, `memoizer: cached value of key "key" was mutated`, func() {
+		_, _ = m.Memoize("key", fn)
+	})
+	assert.True(t, m.Config().MutationDetection)
+}
+
+func TestMutationDetectionReportsNestedMutationsOnce(t *testing.T) {
+	var mutated []string
+	m := NewMemoizerWithOptions[*profile](&MutationDetectionOption{OnMutation: func(key string) {
+		mutated = append(mutated, key)
+	}})
+	fn := func() (*profile, error) {
+		return &profile{Name: "a", Scores: map[string]int{"x": 1, "y": 2}, Friend: &profile{Name: "b"}, notes: []string{"n"}}, nil
+	}
+
+	value, err := m.Memoize("key", fn)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err = m.Memoize("key", fn)
+		require.NoError(t, err)
+	}
+	assert.Empty(t, mutated, "map iteration order does not affect the checksum")
+
+	value.Friend.Name = "c"
+	_, err = m.Memoize("key", fn)
+	require.NoError(t, err)
+	_, _ = m.Peek("key")
+	assert.Equal(t, []string{"key"}, mutated)
+
+	value.Scores["x"] = 3
+	value.notes[0] = "changed"
+	_, _ = m.Peek("key")
+	assert.Equal(t, []string{"key", "key"}, mutated)
+}
+
+func TestChecksumHandlesCycles(t *testing.T) {
+	cyclic := &profile{Name: "a"}
+	cyclic.Friend = cyclic
+	first := checksum(cyclic)
+	assert.Equal(t, first, checksum(cyclic))
+
+	cyclic.Name = "b"
+	assert.NotEqual(t, first, checksum(cyclic))
+}
@@ -0,0 +1,49 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCombinesOptions(t *testing.T) {
+	var misses []string
+	m := New[int](
+		WithDefaultExpiration(time.Minute),
+		WithCleanupInterval(time.Hour),
+		WithHooks(Hooks[int]{OnMiss: func(key string) { misses = append(misses, key) }}),
+	)
+
+	_, err := m.Memoize("key", func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key"}, misses)
+
+	config := m.Config()
+	assert.Equal(t, time.Minute, config.DefaultExpiration)
+	assert.Equal(t, time.Hour, config.CleanupInterval)
+	assert.True(t, config.Hooks)
+	inventory := m.Inventory()
+	require.Len(t, inventory, 1)
+	assert.InDelta(t, float64(time.Minute), float64(inventory[0].RemainingTTL), float64(time.Second))
+}
+
+func TestNewDefaults(t *testing.T) {
+	config := New[int]().Config()
+	assert.Equal(t, NoExpiration, config.DefaultExpiration)
+	assert.Zero(t, config.CleanupInterval)
+
+	// The cleanup interval only applies to the default store.
+	store := newMapStore()
+	m := New[int](WithStore(store), WithCleanupInterval(time.Hour), WithDefaultExpiration(0))
+	assert.Same(t, Store(store), m.store)
+	assert.Zero(t, m.Config().CleanupInterval)
+	assert.Equal(t, NoExpiration, m.Config().DefaultExpiration)
+}
+
+func TestDeprecatedConstructors(t *testing.T) {
+	assert.Equal(t, NoExpiration, NewMemoizer[int]().DefaultExpiration())
+	assert.Equal(t, time.Minute, NewMemoizerWithCacheExpiration[int](time.Minute).DefaultExpiration())
+	assert.Equal(t, 5, NewMemoizerWithOptions[int](WithMaxEntries(5)).Config().MaxEntries)
+}
@@ -0,0 +1,33 @@
+package memoizer
+
+import (
+	"fmt"
+	"time"
+)
+
+// OnceWithin runs fn at most once per key within window, which makes it a deduplication
+// primitive for idempotent operations such as webhook or event processing. The first call
+// for a key runs fn and, if fn succeeds, caches a marker for window; later calls for the
+// same key within window are skipped. A failed fn caches nothing, so the next call retries.
+//
+// skipped reports whether fn was not run by this call, either because the key was already
+// marked or because the call joined a concurrent in-flight run; in the latter case err is
+// the outcome of that run. A window that is not positive would mark the key forever, so it
+// fails with an error wrapping ErrInvalidOption without running fn.
+//
+// OnceWithin is a function rather than a method because Go does not allow methods on a
+// single instantiation of a generic type.
+func OnceWithin(m *Memoizer[struct{}], key string, window time.Duration, fn func() error) (skipped bool, err error) {
+	if window <= 0 {
+		return false, fmt.Errorf("%w: OnceWithin window %v must be positive", ErrInvalidOption, window)
+	}
+
+	ran := false
+	_, err = m.Memoize(key, func() (struct{}, error) {
+		ran = true
+		return struct{}{}, fn()
+	}, WithExpirationFor(func(struct{}) time.Duration {
+		return window
+	}))
+	return !ran, err
+}
@@ -0,0 +1,60 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnceWithin(t *testing.T) {
+	memoizer := NewMemoizer[struct{}]()
+	processed := 0
+	process := func() error {
+		processed++
+		return nil
+	}
+
+	skipped, err := OnceWithin(memoizer, "event-1", 100*time.Millisecond, process)
+	require.NoError(t, err)
+	assert.False(t, skipped)
+
+	// Duplicates within the window are skipped.
+	skipped, err = OnceWithin(memoizer, "event-1", 100*time.Millisecond, process)
+	require.NoError(t, err)
+	assert.True(t, skipped)
+	assert.Equal(t, 1, processed)
+
+	// Failed processing is not marked, so it can be retried.
+	skipped, err = OnceWithin(memoizer, "event-2", 100*time.Millisecond, func() error {
+		return errors.New("intentional error")
+	})
+	require.Error(t, err)
+	assert.False(t, skipped)
+	skipped, err = OnceWithin(memoizer, "event-2", 100*time.Millisecond, process)
+	require.NoError(t, err)
+	assert.False(t, skipped)
+	assert.Equal(t, 2, processed)
+
+	// After the window, the event is processed again.
+	time.Sleep(150 * time.Millisecond)
+	skipped, err = OnceWithin(memoizer, "event-1", 100*time.Millisecond, process)
+	require.NoError(t, err)
+	assert.False(t, skipped)
+	assert.Equal(t, 3, processed)
+}
+
+func TestOnceWithinRejectsNonPositiveWindow(t *testing.T) {
+	memoizer := NewMemoizer[struct{}]()
+
+	_, err := OnceWithin(memoizer, "event", 0, func() error {
+		t.Fatal("ran with a zero window")
+		return nil
+	})
+	require.ErrorIs(t, err, ErrInvalidOption)
+
+	_, ok := memoizer.Peek("event")
+	assert.False(t, ok, "the key should not be marked")
+}
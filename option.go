@@ -1,6 +1,11 @@
 package memoizer
 
-import "time"
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
 
 // Option is an interface for configuring the Memoizer's behavior.
 // Implementations of this interface can be passed to the Memoize function
@@ -25,6 +30,1260 @@ type ExpirationOption struct {
 //	    // Custom logic to determine expiration based on the result
 //	    return time.Hour
 //	}))
+//
+// Deprecated: Use WithTTL, or WithExpirationFor, whose callback receives the result as T.
 var WithExpiration = func(callback func(result interface{}) time.Duration) Option {
 	return &ExpirationOption{Callback: callback}
 }
+
+// ExpirationForOption is a struct that implements the Option interface.
+// It is the typed counterpart of ExpirationOption: its Callback receives the result as T.
+type ExpirationForOption[T any] struct {
+	Callback func(result T) time.Duration
+}
+
+// WithExpirationFor is like WithExpiration, but the callback receives the result with its
+// concrete type, so it needs no type assertion. T must match the type parameter of the
+// Memoizer; otherwise the option is ignored.
+//
+// Example usage:
+//
+//	memoizer.Memoize("key", fetchToken, memoizer.WithExpirationFor(func(token Token) time.Duration {
+//	    return time.Until(token.ExpiresAt)
+//	}))
+func WithExpirationFor[T any](callback func(result T) time.Duration) Option {
+	return &ExpirationForOption[T]{Callback: callback}
+}
+
+// TTLOption is a struct that implements the Option interface.
+// It holds a fixed expiration for the result.
+type TTLOption struct {
+	TTL time.Duration
+}
+
+// WithTTL returns an Option that caches the result for d, for the common case where the
+// expiration is known up front and does not depend on the result. A d of zero stands for the
+// default expiration, and NoExpiration keeps the result until it is removed. It replaces any
+// WithExpiration or WithExpirationFor of the call.
+//
+// Example usage:
+//
+//	memoizer.Memoize("key", myFunc, memoizer.WithTTL(time.Hour))
+var WithTTL = func(d time.Duration) Option {
+	return &TTLOption{TTL: d}
+}
+
+// AdmissionThresholdOption is a struct that implements the Option interface.
+// It holds the number of times a key must be requested before its result is cached.
+type AdmissionThresholdOption struct {
+	Threshold int
+}
+
+// WithAdmissionThreshold returns an Option that only caches a key's result once the key
+// has missed at least n times. The first n-1 misses recompute the value without storing it,
+// which keeps one-off lookups from polluting the cache. Values of n below 2 cache immediately.
+//
+// Request counts for keys that have not been admitted yet are kept in a small bounded map;
+// when it is full, an arbitrary candidate is dropped and has to start counting again.
+var WithAdmissionThreshold = func(n int) Option {
+	return &AdmissionThresholdOption{Threshold: n}
+}
+
+// ContextKeyAugmenterOption is a struct that implements the Option interface.
+// It contains an Augment function that derives the effective cache key from the context.
+type ContextKeyAugmenterOption struct {
+	Augment func(ctx context.Context, key string) string
+}
+
+// WithContextKeyAugmenter returns an Option that lets MemoizeContext incorporate values
+// carried by the context (such as a tenant ID) into the cache key. The callback receives
+// the nominal key and returns the key that is actually used, so the same nominal key
+// under different contexts is cached separately. Memoize ignores this option.
+//
+// Example usage:
+//
+//	memoizer.MemoizeContext(ctx, "settings", loadSettings, memoizer.WithContextKeyAugmenter(func(ctx context.Context, key string) string {
+//	    return tenantFromContext(ctx) + ":" + key
+//	}))
+var WithContextKeyAugmenter = func(augment func(ctx context.Context, key string) string) Option {
+	return &ContextKeyAugmenterOption{Augment: augment}
+}
+
+// DetachedContextOption is a struct that implements the Option interface.
+// It runs MemoizeContext computations with a context that is never cancelled.
+type DetachedContextOption struct{}
+
+// WithDetachedContext returns an Option that makes MemoizeContext run the memoized function
+// with a context that carries the caller's values but not its cancellation or deadline.
+// Since a computation is shared by every concurrent caller of the key, this keeps one caller
+// giving up from failing the computation for the others; each caller still stops waiting
+// when its own context is done. Memoize ignores this option.
+var WithDetachedContext = func() Option {
+	return &DetachedContextOption{}
+}
+
+// StoreOption is a struct that implements the Option interface.
+// It holds the Store that backs a Memoizer.
+type StoreOption struct {
+	Store Store
+}
+
+// WithStore returns a constructor Option for New that stores cached results in store instead of
+// the default in-memory GoCacheStore.
+var WithStore = func(store Store) Option {
+	return &StoreOption{Store: store}
+}
+
+// MaxEntriesOption is a struct that implements the Option interface.
+// It bounds the number of entries held by a Memoizer's default store.
+type MaxEntriesOption struct {
+	MaxEntries int
+}
+
+// WithMaxEntries returns a constructor Option for New that backs the Memoizer with an LRUStore
+// holding at most n entries, so that a long-running service cannot grow the cache without
+// bound. Once the store is full, storing a result evicts the least recently used entry. It is
+// ignored if a store is also configured with WithStore.
+var WithMaxEntries = func(n int) Option {
+	return &MaxEntriesOption{MaxEntries: n}
+}
+
+// MaxCostOption is a struct that implements the Option interface.
+// It bounds the total cost of the entries held by a Memoizer's default store.
+type MaxCostOption struct {
+	MaxCost int64
+	Cost    func(value interface{}) int64
+}
+
+// WithMaxCost returns a constructor Option for New that backs the Memoizer with an LRUStore
+// whose entries cost at most maxCost in total, for caches holding results of varying sizes.
+// cost reports the cost of a result, typically its approximate size in bytes; if it is nil,
+// results implementing Sizer report their size and other results cost 1. It can be combined
+// with WithMaxEntries, and is ignored if a store is configured with WithStore.
+//
+// Example usage:
+//
+//	m := memoizer.New[[]byte](memoizer.WithMaxCost(64<<20, func(value interface{}) int64 {
+//	    return int64(len(value.([]byte)))
+//	}))
+//
+// Deprecated: Use WithMaxCostFor, whose cost function receives the result as T.
+var WithMaxCost = func(maxCost int64, cost func(value interface{}) int64) Option {
+	return &MaxCostOption{MaxCost: maxCost, Cost: cost}
+}
+
+// WithMaxCostFor is like WithMaxCost, but cost receives the result with its concrete type. T
+// must match the type parameter of the Memoizer; a value of another type costs as much as the
+// zero T.
+//
+// Example usage:
+//
+//	m := memoizer.New[[]byte](memoizer.WithMaxCostFor(64<<20, func(value []byte) int64 {
+//	    return int64(len(value))
+//	}))
+func WithMaxCostFor[T any](maxCost int64, cost func(value T) int64) Option {
+	if cost == nil {
+		return &MaxCostOption{MaxCost: maxCost}
+	}
+	return &MaxCostOption{MaxCost: maxCost, Cost: func(value interface{}) int64 {
+		typed, _ := value.(T)
+		return cost(typed)
+	}}
+}
+
+// DefaultCallOptionsOption is a struct that implements the Option interface.
+// It holds the per-call Options applied to every call of a Memoizer.
+type DefaultCallOptionsOption struct {
+	Options []Option
+}
+
+// WithDefaultCallOptions returns a constructor Option for New that applies per-call options,
+// such as WithExpiration, WithCachePredicate or WithRetry, to every call of the Memoizer, so
+// that they need not be passed at each call site. Options passed to a call take precedence over
+// defaults of the same kind; for example, a call's WithExpiration replaces a default
+// WithExpiration, while a default WithRetry still applies to it. Constructor options, such as
+// WithClone, are passed to New directly.
+//
+// Example usage:
+//
+//	users := memoizer.New[User](memoizer.WithDefaultCallOptions(
+//	    memoizer.WithRetry(3, memoizer.ExponentialBackoff(100*time.Millisecond, time.Second)),
+//	    memoizer.WithTTLJitter(0.1),
+//	))
+var WithDefaultCallOptions = func(options ...Option) Option {
+	return &DefaultCallOptionsOption{Options: options}
+}
+
+// DefaultExpirationOption is a struct that implements the Option interface.
+// It holds the expiration of cached results that do not specify their own.
+type DefaultExpirationOption struct {
+	Expiration time.Duration
+}
+
+// WithDefaultExpiration returns a constructor Option for New that makes cached results expire
+// after d unless a call specifies its own expiration. A d of zero or NoExpiration keeps them
+// until they are removed, which is the default.
+var WithDefaultExpiration = func(d time.Duration) Option {
+	return &DefaultExpirationOption{Expiration: d}
+}
+
+// CleanupIntervalOption is a struct that implements the Option interface.
+// It holds how often expired entries are purged from the default store.
+type CleanupIntervalOption struct {
+	Interval time.Duration
+}
+
+// WithCleanupInterval returns a constructor Option for New that purges expired entries from the
+// default GoCacheStore every interval, instead of only when they are overwritten or read. It has
+// no effect with WithStore, whose store manages its own cleanup, or with WithMaxEntries and
+// WithMaxCost, whose LRUStore evicts entries instead.
+var WithCleanupInterval = func(interval time.Duration) Option {
+	return &CleanupIntervalOption{Interval: interval}
+}
+
+// GlobalCircuitOption is a struct that implements the Option interface.
+// It configures the Memoizer-wide circuit breaker installed by WithGlobalCircuit.
+type GlobalCircuitOption struct {
+	ErrorRate float64
+	Window    time.Duration
+	Cooldown  time.Duration
+	// MinRequests is the number of computations a window must count before its error rate is
+	// evaluated, or DefaultCircuitMinRequests if it is not positive.
+	MinRequests int
+}
+
+// WithGlobalCircuit returns a constructor Option for New that installs a system-wide circuit
+// breaker. When the fraction of failed computations across all keys exceeds errorRate within
+// a window of at least DefaultCircuitMinRequests computations, or of MinRequests when the
+// GlobalCircuitOption is built directly, every cache miss fails fast with ErrCircuitOpen for
+// the duration of cooldown, shedding load from a struggling backend, or is served the expired
+// value retained by WithServeStaleOnError, if any. Cache hits are unaffected. Once the
+// cooldown elapses, the circuit closes and counting starts afresh.
+//
+// Example usage:
+//
+//	m := memoizer.New[int](memoizer.WithGlobalCircuit(0.5, time.Minute, 10*time.Second))
+var WithGlobalCircuit = func(errorRate float64, window time.Duration, cooldown time.Duration) Option {
+	return &GlobalCircuitOption{ErrorRate: errorRate, Window: window, Cooldown: cooldown}
+}
+
+// MaxConcurrentComputationsOption is a struct that implements the Option interface.
+// It bounds the number of computations that run at once across all keys.
+type MaxConcurrentComputationsOption struct {
+	MaxConcurrent int
+}
+
+// WithMaxConcurrentComputations returns a constructor Option for New that runs at most n
+// computations at once across all keys, so that a Memoizer fronting an expensive backend never
+// sends it more than n concurrent requests. Computations of further keys wait for a slot;
+// callers of a key that is already being computed share its computation as usual. Cache hits
+// are unaffected. A computation that memoizes other keys holds its slot while they are
+// computed, so n must exceed the depth of such nesting.
+var WithMaxConcurrentComputations = func(n int) Option {
+	return &MaxConcurrentComputationsOption{MaxConcurrent: n}
+}
+
+// CoalescedInvalidationOption is a struct that implements the Option interface.
+// It holds the window within which WithCoalescedInvalidation collapses invalidations.
+type CoalescedInvalidationOption struct {
+	Window time.Duration
+}
+
+// WithCoalescedInvalidation returns a constructor Option for New that collapses bursts of Delete
+// calls for the same key, such as those of a chatty upstream that sends many invalidation events
+// for a key, into a single deletion applied at the end of window. The first Delete of a key
+// starts the window, and further ones within it have no additional effect, which saves the
+// recomputations that deleting the key each time would cause. In exchange, a deleted entry may
+// be served for up to window after Delete returns. Close applies the pending deletions right
+// away. A window that is not positive disables coalescing.
+var WithCoalescedInvalidation = func(window time.Duration) Option {
+	return &CoalescedInvalidationOption{Window: window}
+}
+
+// MaxErrorEntriesOption is a struct that implements the Option interface.
+// It bounds the number of errors cached by WithErrorCaching.
+type MaxErrorEntriesOption struct {
+	MaxErrorEntries int
+}
+
+// WithMaxErrorEntries returns a constructor Option for New that holds at most n errors cached
+// by WithErrorCaching, or DefaultMaxErrorEntries if n is not positive. Cached errors are kept
+// apart from cached results, so that an outage producing many unique failures cannot evict
+// successful results: once the bound is reached, expired errors are dropped, and then the
+// least recently used ones.
+var WithMaxErrorEntries = func(n int) Option {
+	return &MaxErrorEntriesOption{MaxErrorEntries: n}
+}
+
+// StrictTypesOption is a struct that implements the Option interface.
+// It makes values of the wrong type in the store panic instead of being treated as misses.
+type StrictTypesOption struct{}
+
+// WithStrictTypes returns a constructor Option for New that makes reading a cached value that
+// is not of type T, and that a WithFlexibleDecode decoder does not convert, panic with an error
+// wrapping ErrTypeMismatch. By default, such a value is treated as a cache miss, so that it is
+// recomputed and overwritten, and a warning is logged with WithLogger.
+var WithStrictTypes = func() Option {
+	return &StrictTypesOption{}
+}
+
+// OptionValidationOption is a struct that implements the Option interface.
+// It makes calls validate their options.
+type OptionValidationOption struct{}
+
+// WithOptionValidation returns a constructor Option for New that makes every call validate
+// its options, together with the defaults set by WithDefaultCallOptions, as Validate does. A
+// call with invalid or conflicting options returns the error from Validate without running
+// its function or reading the cache. Validation walks the options of every call, so it is best
+// enabled in development and tests.
+var WithOptionValidation = func() Option {
+	return &OptionValidationOption{}
+}
+
+// KeyHasherOption is a struct that implements the Option interface.
+// It holds the function transforming keys before they are stored.
+type KeyHasherOption struct {
+	Hasher func(key string) string
+}
+
+// WithKeyHasher returns a constructor Option for New that transforms every key with hasher
+// before it is stored, such as with HashKey or HashLongKeys, to bound the memory taken by very
+// long keys, such as SQL text or URLs, and to fit stores that limit key lengths. hasher must
+// be deterministic, and should be collision-resistant: keys with the same hash share an entry.
+//
+// Keys are transformed as they are passed to the Memoizer, so everything it reports, such as
+// the keys of Inventory and Snapshot, the keys passed to hooks, observers and fallbacks, and
+// the keys matched by DeletePrefix and DeleteMatch, are the transformed keys. The keys of the
+// map returned by MemoizeMulti are the keys returned by its function.
+//
+// Example usage:
+//
+//	queries := memoizer.New[[]Row](memoizer.WithKeyHasher(memoizer.HashLongKeys(200)))
+var WithKeyHasher = func(hasher func(key string) string) Option {
+	return &KeyHasherOption{Hasher: hasher}
+}
+
+// MutationDetectionOption is a struct that implements the Option interface.
+// It enables the mutation detection of WithMutationDetection. If OnMutation is not nil, it is
+// called with the key of a mutated value instead of panicking.
+type MutationDetectionOption struct {
+	OnMutation func(key string)
+}
+
+// WithMutationDetection returns a constructor Option for New that helps find aliasing bugs,
+// where a caller mutates a value shared through the cache. A checksum of each stored value is
+// recorded and verified whenever the value is read from the cache; if they differ, the Memoizer
+// panics, naming the key, and logs an error with WithLogger. Construct a
+// MutationDetectionOption with OnMutation set to report mutations instead. Each mutation is
+// reported once. Hashing every value on every read is expensive, so it is meant for debugging
+// and tests. WithClone prevents such mutations instead.
+var WithMutationDetection = func() Option {
+	return &MutationDetectionOption{}
+}
+
+// CloneOption is a struct that implements the Option interface.
+// It contains the Clone function that copies cached values handed out to callers.
+type CloneOption[T any] struct {
+	Clone func(value T) T
+}
+
+// WithClone returns a constructor Option for New that hands out a copy of cached and computed
+// values made by clone, instead of the value shared by the cache and every caller, so that a
+// caller mutating its value cannot corrupt the cached entry. DeepCopy copies any value by
+// reflection, such as for slices and maps; a dedicated function is faster. Values passed to Set
+// are stored as is.
+//
+// Example usage:
+//
+//	m := memoizer.New[[]string](memoizer.WithClone(slices.Clone[[]string]))
+func WithClone[T any](clone func(value T) T) Option {
+	return &CloneOption[T]{Clone: clone}
+}
+
+// CircuitBreakerOption is a struct that implements the Option interface.
+// It configures the per-key circuit breakers installed by WithCircuitBreaker.
+type CircuitBreakerOption struct {
+	Failures int
+	Cooldown time.Duration
+}
+
+// WithCircuitBreaker returns a constructor Option for New that installs a circuit breaker per
+// key. Once the computation of a key fails failures times in a row, cache misses of that key
+// fail fast with ErrCircuitOpen for the duration of cooldown, instead of calling a failing
+// dependency again; other keys are unaffected. With WithServeStaleOnError, a retained expired
+// value is served instead, as with the global circuit breaker. After the cooldown, the next
+// computation is attempted, and the circuit trips again if it fails. A failure no longer counts
+// towards tripping the circuit once cooldown has passed without another failure of the key.
+var WithCircuitBreaker = func(failures int, cooldown time.Duration) Option {
+	return &CircuitBreakerOption{Failures: failures, Cooldown: cooldown}
+}
+
+// FlexibleDecodeOption is a struct that implements the Option interface.
+// It contains a Decode function that converts a raw cached value into T.
+type FlexibleDecodeOption[T any] struct {
+	Decode func(raw interface{}) (T, bool)
+}
+
+// WithFlexibleDecode returns a constructor Option for New that installs a decoder for cached
+// values that are not already of type T, such as byte slices written in a serialized format
+// during a backend migration. The decoder is only called when the direct type assertion fails;
+// if it reports false, the value is treated as a cache miss, like any other value that is not
+// of type T.
+//
+// Example usage:
+//
+//	m := memoizer.New[int](memoizer.WithFlexibleDecode(func(raw interface{}) (int, bool) {
+//	    b, ok := raw.([]byte)
+//	    if !ok {
+//	        return 0, false
+//	    }
+//	    n, err := strconv.Atoi(string(b))
+//	    return n, err == nil
+//	}))
+func WithFlexibleDecode[T any](decode func(raw interface{}) (T, bool)) Option {
+	return &FlexibleDecodeOption[T]{Decode: decode}
+}
+
+// RecorderOption is a struct that implements the Option interface.
+// It holds the Recorder that captures the Memoizer's cache operations.
+type RecorderOption struct {
+	Recorder *Recorder
+}
+
+// WithRecorder returns a constructor Option for New that captures every cache hit and
+// computation into the given Recorder, for later use with Replay.
+var WithRecorder = func(recorder *Recorder) Option {
+	return &RecorderOption{Recorder: recorder}
+}
+
+// EagerBackgroundFillOption is a struct that implements the Option interface.
+// It makes a cache miss return immediately while the value is computed in the background.
+type EagerBackgroundFillOption struct{}
+
+// WithEagerBackgroundFill returns an Option that makes a cold miss return the zero value of T
+// and a nil error immediately, while the function runs in the background (deduplicated via
+// singleflight) to fill the cache for subsequent calls. It suits best-effort consumers that can
+// render a placeholder: callers must tolerate receiving the zero value until the fill completes.
+// Errors and panics of the background computation are discarded, and the next miss retries.
+// It has no effect on calls made with WithForceRefresh, which wait for the recomputation. With
+// MemoizeContext, the function runs with a context that is not cancelled when the call returns.
+var WithEagerBackgroundFill = func() Option {
+	return &EagerBackgroundFillOption{}
+}
+
+// MaxRecursionDepthOption is a struct that implements the Option interface.
+// It bounds how deeply memoized computations may nest.
+type MaxRecursionDepthOption struct {
+	Depth int
+}
+
+// WithMaxRecursionDepth returns a constructor Option for New that fails computations with
+// ErrMaxRecursionDepth once more than n computations of the Memoizer are nested, such as in a
+// runaway recursive memoized function. Nesting is counted per goroutine, and also through the
+// context that MemoizeContext passes to the memoized function, which follows the computation
+// onto other goroutines. Reentrant computation of the same key is always reported as
+// ErrReentrantComputation, regardless of this option.
+var WithMaxRecursionDepth = func(n int) Option {
+	return &MaxRecursionDepthOption{Depth: n}
+}
+
+// TTLBoundsOption is a struct that implements the Option interface.
+// It holds a floor or a ceiling applied to the expiration of cached results.
+type TTLBoundsOption struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// WithMinTTL returns an Option that raises any resolved expiration below d to d. It guards
+// against expiration callbacks that accidentally return zero or a negative duration, which
+// would otherwise fall back to the default expiration or never expire. An expiration of
+// exactly NoExpiration, whether returned by a callback or configured as the Memoizer's
+// default, is treated as deliberate and is not raised.
+var WithMinTTL = func(d time.Duration) Option {
+	return &TTLBoundsOption{Min: d}
+}
+
+// WithMaxTTL returns an Option that lowers any resolved expiration above d to d. Entries that
+// would never expire, including under a default of NoExpiration, are capped to d as well.
+var WithMaxTTL = func(d time.Duration) Option {
+	return &TTLBoundsOption{Max: d}
+}
+
+// PanicFallbackOption is a struct that implements the Option interface.
+// It contains a Fallback computation that runs when the memoized function panics.
+// If Cache is set, a successful fallback result is cached like a regular result.
+type PanicFallbackOption[T any] struct {
+	Fallback func() (T, error)
+	Cache    bool
+}
+
+// WithPanicFallback returns an Option that recovers a panic in the memoized function and runs
+// fallback instead, returning its result. The fallback result is not cached, so the primary
+// function is retried on the next call; construct a PanicFallbackOption with Cache set to cache
+// it. If the fallback returns an error or panics too, the original panic propagates.
+func WithPanicFallback[T any](fallback func() (T, error)) Option {
+	return &PanicFallbackOption[T]{Fallback: fallback}
+}
+
+// FallbackOption is a struct that implements the Option interface.
+// It contains a Fallback function that runs when the memoized function fails.
+// If Cache is set, a successful fallback result is cached like a regular result.
+type FallbackOption[T any] struct {
+	Fallback func(key string, err error) (T, error)
+	Cache    bool
+}
+
+// WithFallback returns an Option that runs fallback when the memoized function returns an
+// error, including after any retries, and returns its result instead, such as a default value
+// or data from an alternate source. The fallback result is not cached, so the primary function
+// is retried on the next call; construct a FallbackOption with Cache set to cache it. An
+// expired value retained by WithServeStaleOnError takes precedence over the fallback. If the
+// fallback returns an error, that error is returned; it may wrap err.
+func WithFallback[T any](fallback func(key string, err error) (T, error)) Option {
+	return &FallbackOption[T]{Fallback: fallback}
+}
+
+// AuditEntry describes a computation that actually ran, as reported to WithAuditLog.
+type AuditEntry struct {
+	Key      string
+	Time     time.Time
+	Duration time.Duration
+	// Summary is the caller-provided summary of a successful result.
+	Summary string
+	Err     error
+}
+
+// AuditLogOption is a struct that implements the Option interface.
+// It contains the Log callback that receives an AuditEntry for every computation,
+// and an optional Summarize function that condenses the result for the entry.
+type AuditLogOption struct {
+	Log       func(entry AuditEntry)
+	Summarize func(result interface{}) string
+}
+
+// WithAuditLog returns an Option that calls log with an AuditEntry whenever the memoized
+// function actually runs, and never for results served from the cache or shared with a
+// concurrent caller. summarize, which may be nil, is called with successful results to fill
+// in the entry's Summary.
+//
+// Example usage:
+//
+//	memoizer.Memoize("key", myFunc, memoizer.WithAuditLog(auditLogger.Log, func(result interface{}) string {
+//	    return fmt.Sprintf("%d records", len(result.([]Record)))
+//	}))
+//
+// Deprecated: Use WithAuditLogFor, whose summarize function receives the result as T.
+var WithAuditLog = func(log func(entry AuditEntry), summarize func(result interface{}) string) Option {
+	return &AuditLogOption{Log: log, Summarize: summarize}
+}
+
+// WithAuditLogFor is like WithAuditLog, but summarize receives the result with its concrete
+// type. T must match the type parameter of the Memoizer.
+//
+// Example usage:
+//
+//	memoizer.Memoize("key", myFunc, memoizer.WithAuditLogFor(auditLogger.Log, func(records []Record) string {
+//	    return fmt.Sprintf("%d records", len(records))
+//	}))
+func WithAuditLogFor[T any](log func(entry AuditEntry), summarize func(result T) string) Option {
+	if summarize == nil {
+		return &AuditLogOption{Log: log}
+	}
+	return &AuditLogOption{Log: log, Summarize: func(result interface{}) string {
+		typed, _ := result.(T)
+		return summarize(typed)
+	}}
+}
+
+// WindowedStatsOption is a struct that implements the Option interface.
+// It enables the rolling-window statistics reported by WindowedStats.
+type WindowedStatsOption struct{}
+
+// WithWindowedStats returns a constructor Option for New that keeps hit and miss counts over
+// rolling 1, 5 and 15 minute windows, as reported by WindowedStats. It is opt-in because every
+// lookup then updates a shared counter.
+var WithWindowedStats = func() Option {
+	return &WindowedStatsOption{}
+}
+
+// AfterComputeOption is a struct that implements the Option interface.
+// It contains a Hook invoked after a successful computation.
+type AfterComputeOption[T any] struct {
+	Hook func(key string, value T, set func(k string, v T, opts ...Option))
+}
+
+// WithAfterCompute returns an Option that calls hook after the memoized function succeeds,
+// passing the key, the computed value and a set function that stores additional entries in
+// the same Memoizer, with their own expiration options. This lets a call site fan a result
+// out into related cache entries. The hook runs inside the computation, so it is not called
+// for cache hits, and callers sharing the computation wait for it to finish.
+//
+// Example usage:
+//
+//	memoizer.Memoize("user:1", loadUser, memoizer.WithAfterCompute(func(key string, u User, set func(string, User, ...memoizer.Option)) {
+//	    set("user-by-email:"+u.Email, u)
+//	}))
+func WithAfterCompute[T any](hook func(key string, value T, set func(k string, v T, opts ...Option))) Option {
+	return &AfterComputeOption[T]{Hook: hook}
+}
+
+// ErrResultInvalid is returned, along with the last computed result, when no attempt allowed
+// by WithResultRetry produced a result that passed validation.
+var ErrResultInvalid = errors.New("result failed validation")
+
+// ResultRetryOption is a struct that implements the Option interface.
+// It holds the maximum number of attempts and the Valid function that checks each result.
+type ResultRetryOption[T any] struct {
+	MaxAttempts int
+	Valid       func(result T) bool
+}
+
+// WithResultRetry returns an Option that re-runs the memoized function, up to maxAttempts
+// times in total, until valid accepts its result. This handles results that are well-formed
+// but semantically incomplete. Only a validated result is cached. If no attempt validates,
+// nothing is cached and the last result is returned with ErrResultInvalid. Errors returned
+// by the function are not retried.
+func WithResultRetry[T any](maxAttempts int, valid func(result T) bool) Option {
+	return &ResultRetryOption[T]{MaxAttempts: maxAttempts, Valid: valid}
+}
+
+// StaleWhileRevalidateOption is a struct that implements the Option interface.
+// It holds how long an entry may be served stale after it expires.
+type StaleWhileRevalidateOption struct {
+	StaleFor time.Duration
+}
+
+// WithStaleWhileRevalidate returns an Option that keeps a cached result for staleFor beyond
+// its expiration. Within that window, a lookup returns the stale value immediately and starts
+// a background recomputation, deduplicated via singleflight, to refresh it; after the window,
+// the entry is a regular miss. Results that never expire are never stale.
+//
+// Staleness is tracked by the Memoizer that stored the entry. Other Memoizers sharing the same
+// Store see the entry as fresh until it leaves the store.
+var WithStaleWhileRevalidate = func(staleFor time.Duration) Option {
+	return &StaleWhileRevalidateOption{StaleFor: staleFor}
+}
+
+// SoftHardTTLOption is a struct that implements the Option interface.
+// It holds the soft and hard expirations of a cached result.
+type SoftHardTTLOption struct {
+	Soft time.Duration
+	Hard time.Duration
+}
+
+// WithSoftHardTTL returns an Option that gives a cached result two expirations. After soft, the
+// next lookup returns the cached value immediately and refreshes it in the background, deduplicated
+// via singleflight; after hard, the entry is a regular miss. It is equivalent to an expiration of
+// soft combined with WithStaleWhileRevalidate(hard - soft), and overrides both; hard must not be
+// shorter than soft.
+var WithSoftHardTTL = func(soft, hard time.Duration) Option {
+	return &SoftHardTTLOption{Soft: soft, Hard: hard}
+}
+
+// ServeStaleOnErrorOption is a struct that implements the Option interface.
+// It holds how long an expired entry is retained as a fallback for failed recomputations.
+type ServeStaleOnErrorOption struct {
+	RetainFor time.Duration
+	// Report makes the call return the stale value together with a *StaleError wrapping the
+	// recomputation error, instead of with a nil error.
+	Report bool
+}
+
+// WithServeStaleOnError returns an Option that retains a cached result for retainFor beyond its
+// expiration. An expired entry is a miss, but if recomputing it fails while the expired value is
+// still retained, that value is returned with a nil error instead of the failure. Construct a
+// ServeStaleOnErrorOption with Report set to let callers detect that the value is stale.
+// Results that never expire are never retained beyond it.
+var WithServeStaleOnError = func(retainFor time.Duration) Option {
+	return &ServeStaleOnErrorOption{RetainFor: retainFor}
+}
+
+// ErrorCachingOption is a struct that implements the Option interface.
+// It contains a TTL function that determines how long an error is cached.
+type ErrorCachingOption struct {
+	TTL func(err error) time.Duration
+}
+
+// WithErrorCaching returns an Option that caches errors returned by the memoized function for
+// ttl, so that a failing upstream is not called again by every request for the key. Until the
+// error expires, callers of the key receive the original error value without running the
+// function. Storing a value for the key clears its cached error. Cached errors are held apart
+// from cached results, and bounded separately by WithMaxErrorEntries. By default, errors are
+// not cached.
+var WithErrorCaching = func(ttl time.Duration) Option {
+	return &ErrorCachingOption{TTL: func(error) time.Duration { return ttl }}
+}
+
+// WithErrorCachingFunc is like WithErrorCaching, but calls ttl with each error to decide how
+// long to cache it, so that different kinds of failures can be cached differently. A
+// non-positive duration leaves the error uncached.
+//
+// Example usage:
+//
+//	memoizer.Memoize("key", myFunc, memoizer.WithErrorCachingFunc(func(err error) time.Duration {
+//	    if errors.Is(err, ErrNotFound) {
+//	        return time.Minute
+//	    }
+//	    return 0
+//	}))
+var WithErrorCachingFunc = func(ttl func(err error) time.Duration) Option {
+	return &ErrorCachingOption{TTL: ttl}
+}
+
+// TagsOption is a struct that implements the Option interface.
+// It holds the tags attached to a cached result.
+type TagsOption struct {
+	Tags []string
+}
+
+// WithTags returns an Option that attaches tags to the cached result, so that every entry
+// carrying a tag can be removed at once with InvalidateTag, for example all entries derived
+// from a user's data. Storing a result for a key replaces the tags of its previous entry.
+var WithTags = func(tags ...string) Option {
+	return &TagsOption{Tags: tags}
+}
+
+// MaxTagsPerEntryOption is a struct that implements the Option interface.
+// It bounds the number of tags attached to each entry.
+type MaxTagsPerEntryOption struct {
+	MaxTags int
+}
+
+// WithMaxTagsPerEntry returns a constructor Option for New that attaches at most the first n
+// tags given with WithTags to each entry, so that the tag index stays bounded even if a caller
+// derives tags from unbounded data. The entry is still cached; the tags beyond n are dropped
+// and reported to the OnError hook, and to the logger of WithLogger.
+var WithMaxTagsPerEntry = func(n int) Option {
+	return &MaxTagsPerEntryOption{MaxTags: n}
+}
+
+// MaxDistinctTagsOption is a struct that implements the Option interface.
+// It bounds the number of distinct tags in use at once.
+type MaxDistinctTagsOption struct {
+	MaxTags int
+}
+
+// WithMaxDistinctTags returns a constructor Option for New that keeps at most n distinct tags
+// attached to the cached entries at once. A tag that no entry carries yet is dropped while n
+// tags are in use, and reported like the tags dropped by WithMaxTagsPerEntry; tags already in
+// use can still be attached to further entries. A tag stops being in use once no entry carries
+// it.
+var WithMaxDistinctTags = func(n int) Option {
+	return &MaxDistinctTagsOption{MaxTags: n}
+}
+
+// ForceRefreshOption is a struct that implements the Option interface.
+// It makes a call recompute the result even if it is cached.
+type ForceRefreshOption struct{}
+
+// WithForceRefresh returns an Option that bypasses the cached result, or cached error, of the
+// key, recomputes it and replaces the entry, for example right after a known write to the
+// underlying data. The recomputation goes through singleflight, so a computation that is
+// already in flight for the key is shared rather than started anew; call Delete before
+// refreshing to rule out sharing a computation that started before the write.
+var WithForceRefresh = func() Option {
+	return &ForceRefreshOption{}
+}
+
+// ComputeObserverOption is a struct that implements the Option interface.
+// It contains an Observe function that is called after every computation.
+type ComputeObserverOption struct {
+	Observe func(key string, duration time.Duration, err error)
+}
+
+// WithComputeObserver returns a constructor Option for New that calls observe after each run of
+// a memoized function, with the key, how long the function took, including retries, and the
+// error it returned. It is meant for metrics such as latency histograms and error counts;
+// observe runs on the computing goroutine, so it should return quickly.
+var WithComputeObserver = func(observe func(key string, duration time.Duration, err error)) Option {
+	return &ComputeObserverOption{Observe: observe}
+}
+
+// CallTracerOption is a struct that implements the Option interface.
+// It holds the CallTracer that instruments a Memoizer's lookups.
+type CallTracerOption struct {
+	Tracer CallTracer
+}
+
+// WithCallTracer returns a constructor Option for New that reports cache hits and misses to
+// tracer, such as for distributed tracing with the memotel package.
+var WithCallTracer = func(tracer CallTracer) Option {
+	return &CallTracerOption{Tracer: tracer}
+}
+
+// HooksOption is a struct that implements the Option interface.
+// It holds the Hooks that observe a Memoizer's cache lifecycle events.
+type HooksOption[T any] struct {
+	Hooks Hooks[T]
+}
+
+// WithHooks returns a constructor Option for New that calls hooks on cache lifecycle events,
+// for logging and metrics without wrapping every call site. T must match the type parameter of
+// the Memoizer; otherwise the option is ignored.
+//
+// Example usage:
+//
+//	m := memoizer.New[int](memoizer.WithHooks(memoizer.Hooks[int]{
+//	    OnError: func(key string, err error, latency time.Duration) {
+//	        log.Printf("computing %s failed after %v: %v", key, latency, err)
+//	    },
+//	}))
+func WithHooks[T any](hooks Hooks[T]) Option {
+	return &HooksOption[T]{Hooks: hooks}
+}
+
+// LoggerOption is a struct that implements the Option interface.
+// It holds the logger that receives a Memoizer's debug logs.
+type LoggerOption struct {
+	Logger *slog.Logger
+}
+
+// WithLogger returns a constructor Option for New that emits structured debug logs to logger
+// for cache hits and misses, entries evicted or expired by the store, computations shared
+// between callers, and panics recovered from memoized functions. Each record carries the cache
+// key under "key".
+var WithLogger = func(logger *slog.Logger) Option {
+	return &LoggerOption{Logger: logger}
+}
+
+// CodecOption is a struct that implements the Option interface.
+// It holds the Codec used by Export and Restore.
+type CodecOption struct {
+	Codec Codec
+}
+
+// WithCodec returns a constructor Option for New that makes Export and Restore serialize the
+// cache with codec instead of the default JSONCodec.
+var WithCodec = func(codec Codec) Option {
+	return &CodecOption{Codec: codec}
+}
+
+// PersistenceOption is a struct that implements the Option interface.
+// It configures the file a Memoizer's cache is persisted to.
+type PersistenceOption struct {
+	Path     string
+	Interval time.Duration
+	Codec    Codec
+}
+
+// WithPersistence returns a constructor Option for New that loads the cache from the file at
+// path, if it exists, and writes it back every interval, replacing the file atomically, to
+// avoid cold starts after a restart. The periodic writes continue until Close is called, which
+// writes the file one last time; a non-positive interval only loads the file, and Persist
+// writes it on demand. The file is serialized with codec, or with the codec configured by
+// WithCodec if codec is nil. A file that cannot be loaded is ignored, and reported to the
+// logger configured by WithLogger, if any. Persistence requires a store that implements
+// InspectableStore.
+var WithPersistence = func(path string, interval time.Duration, codec Codec) Option {
+	return &PersistenceOption{Path: path, Interval: interval, Codec: codec}
+}
+
+// BroadcasterOption is a struct that implements the Option interface.
+// It holds the Broadcaster that carries invalidations between processes.
+type BroadcasterOption struct {
+	Broadcaster Broadcaster
+}
+
+// WithBroadcaster returns a constructor Option for New that keeps the local caches of several
+// processes consistent. Delete and its variants, InvalidateTag and Flush publish what they
+// removed through broadcaster, and invalidations published by other processes are applied to
+// the local tier of this Memoizer's TieredStore. With any other store, the store itself is
+// treated as local to the process, so a store shared between the processes should be wrapped in
+// a TieredStore.
+var WithBroadcaster = func(broadcaster Broadcaster) Option {
+	return &BroadcasterOption{Broadcaster: broadcaster}
+}
+
+// DistributedLockOption is a struct that implements the Option interface.
+// It configures the distributed lock installed by WithDistributedLock.
+type DistributedLockOption struct {
+	Locker Locker
+	TTL    time.Duration
+	// PollInterval is how often a process waiting for another's computation checks for its
+	// result, or 50 milliseconds if it is not positive.
+	PollInterval time.Duration
+}
+
+// WithDistributedLock returns a constructor Option for New that extends the per-process
+// deduplication of computations to a fleet of processes sharing a store. Before computing a
+// missing key, a process acquires the key's lock from locker; while another process holds it,
+// the process waits for that process to store the result, or serves an expired value retained
+// by WithServeStaleOnError instead. ttl bounds how long a lock is held, so that a crashed
+// holder only delays others that long; it should exceed the longest computation. If locker
+// fails, the process computes the key without the lock.
+var WithDistributedLock = func(locker Locker, ttl time.Duration) Option {
+	return &DistributedLockOption{Locker: locker, TTL: ttl}
+}
+
+// TimeoutOption is a struct that implements the Option interface.
+// It bounds the running time of the memoized function.
+type TimeoutOption struct {
+	Timeout time.Duration
+	// CacheLate caches the outcome of a computation that timed out once it finishes, as it
+	// would have been cached had it finished in time, so that later calls are served from the
+	// cache. It is dropped if the cache was invalidated since the computation started, such as
+	// by Delete, Forget, Flush or ShutdownAndPersist.
+	CacheLate bool
+}
+
+// WithTimeout returns an Option that bounds the running time of the memoized function to d.
+// When d elapses, the callers sharing the computation receive ErrComputeTimeout. The function
+// keeps running, since it cannot be stopped, but its result is discarded; construct a
+// TimeoutOption with CacheLate set to cache it instead. A function that accepts a context, as
+// with MemoizeContext, should rather observe the context's deadline.
+var WithTimeout = func(d time.Duration) Option {
+	return &TimeoutOption{Timeout: d}
+}
+
+// RetryOption is a struct that implements the Option interface.
+// It holds how a failed computation is retried.
+type RetryOption struct {
+	Attempts int
+	Backoff  BackoffFunc
+	// Retryable, if not nil, reports whether an error is transient; other errors are returned
+	// without retrying.
+	Retryable func(err error) bool
+}
+
+// WithRetry returns an Option that runs the memoized function up to attempts times while it
+// returns an error, waiting as long as backoff returns before each retry, or not at all if
+// backoff is nil. Retries happen inside the shared computation, so the callers waiting on it
+// only receive the error of the last attempt. Construct a RetryOption with Retryable set to
+// retry only transient errors.
+//
+// Example usage:
+//
+//	memoizer.Memoize("user:1", loadUser, memoizer.WithRetry(3, memoizer.ExponentialBackoff(100*time.Millisecond, time.Second)))
+var WithRetry = func(attempts int, backoff BackoffFunc) Option {
+	return &RetryOption{Attempts: attempts, Backoff: backoff}
+}
+
+// AutoRefreshOption is a struct that implements the Option interface.
+// It holds the interval at which accessed entries are recomputed.
+type AutoRefreshOption struct {
+	Interval time.Duration
+}
+
+// WithAutoRefresh returns an Option that recomputes the entry in the background every interval
+// once it is cached, for as long as it is served from the cache between refreshes, so that hot
+// keys stay warm and their callers never wait for a computation. Refreshing stops once an
+// interval passes without a cache hit, or once the entry is deleted, and resumes with the next
+// computation. A failed refresh keeps the current entry. interval should be shorter than the
+// entry's expiration. Refreshes reuse the function of the call that cached the entry; with
+// MemoizeContext, combine it with WithDetachedContext.
+var WithAutoRefresh = func(interval time.Duration) Option {
+	return &AutoRefreshOption{Interval: interval}
+}
+
+// EarlyExpirationOption is a struct that implements the Option interface.
+// It holds the Beta factor of probabilistic early expiration.
+type EarlyExpirationOption struct {
+	Beta float64
+}
+
+// WithEarlyExpiration returns an Option that prevents cache stampedes by recomputing entries
+// probabilistically ahead of their expiration, using the XFetch algorithm: each cache hit may
+// start a background recomputation, and becomes likelier to as the entry nears its expiration,
+// the more so the longer the entry took to compute. Callers are served the cached value
+// meanwhile, so that the entry is usually replaced before it expires for everyone at once.
+// beta scales how early recomputations happen; 1 is a good default, and larger values favor
+// earlier ones. Entries that never expire, or that were not computed by the Memoizer, are not
+// recomputed early.
+var WithEarlyExpiration = func(beta float64) Option {
+	return &EarlyExpirationOption{Beta: beta}
+}
+
+// TTLJitterOption is a struct that implements the Option interface.
+// It holds the fraction by which expirations are randomized.
+type TTLJitterOption struct {
+	Fraction float64
+}
+
+// WithTTLJitter returns an Option that randomizes the expiration of each cached result
+// uniformly within ±fraction of it, so that entries cached at the same moment, such as when
+// warming the cache, do not all expire and recompute at once. fraction must be less than 1.
+// The randomized expiration is still subject to WithMinTTL and WithMaxTTL, and results that
+// never expire are unaffected.
+var WithTTLJitter = func(fraction float64) Option {
+	return &TTLJitterOption{Fraction: fraction}
+}
+
+// SlidingExpirationOption is a struct that implements the Option interface.
+// It makes cache hits restart the expiration of the entry.
+type SlidingExpirationOption struct{}
+
+// WithSlidingExpiration returns an Option that restarts the expiration of an entry each time it
+// is served from the cache by a call with this option, resolving it again as if the entry were
+// stored anew, so that frequently read entries, such as sessions, stay cached while idle ones
+// expire. Peek and other plain reads do not affect the expiration. Stale entries being
+// revalidated under WithStaleWhileRevalidate are not extended.
+var WithSlidingExpiration = func() Option {
+	return &SlidingExpirationOption{}
+}
+
+// CachePredicateOption is a struct that implements the Option interface.
+// It contains a Cacheable function that decides whether a result is cached.
+type CachePredicateOption[T any] struct {
+	Cacheable func(result T) bool
+}
+
+// WithCachePredicate returns an Option that only caches successful results for which cacheable
+// returns true. Other results, such as empty lists, partial data or placeholders for data that
+// does not exist yet, are returned to the callers sharing the computation but not stored, so
+// the next call computes them again.
+//
+// Example usage:
+//
+//	memoizer.Memoize("orders:1", loadOrders, memoizer.WithCachePredicate(func(orders []Order) bool {
+//	    return len(orders) > 0
+//	}))
+func WithCachePredicate[T any](cacheable func(result T) bool) Option {
+	return &CachePredicateOption[T]{Cacheable: cacheable}
+}
+
+// PanicAsErrorOption is a struct that implements the Option interface.
+// It makes a panic in the memoized function return as a *PanicError.
+type PanicAsErrorOption struct{}
+
+// WithPanicAsError returns an Option that recovers a panic in the memoized function and returns
+// it to every caller sharing the computation as a *PanicError, carrying the panic value and
+// stack trace, instead of re-panicking in each caller's goroutine. Nothing is cached for the
+// key. MemoizeDoChan always reports panics this way.
+var WithPanicAsError = func() Option {
+	return &PanicAsErrorOption{}
+}
+
+// MaxWaitersOption is a struct that implements the Option interface.
+// It bounds the number of callers waiting on the in-flight computation of a key.
+type MaxWaitersOption struct {
+	MaxWaiters int
+}
+
+// WithMaxWaiters returns an Option that bounds how many callers may wait on the in-flight
+// computation of a key, besides the one running it. A cache miss that would exceed n fails
+// fast with ErrTooManyWaiters instead of piling up another blocked goroutine. Only callers
+// passing this option count towards and are subject to the bound. Cache hits are unaffected.
+var WithMaxWaiters = func(n int) Option {
+	return &MaxWaitersOption{MaxWaiters: n}
+}
+
+// MinRecomputeIntervalOption is a struct that implements the Option interface.
+// It holds the minimum interval between computations of a key.
+type MinRecomputeIntervalOption struct {
+	Interval time.Duration
+}
+
+// WithMinRecomputeInterval returns an Option that keeps the function of a key from running more
+// often than once per d. When a computation fails, its error is replayed to the callers of the
+// key until d has passed since the computation started, as with WithErrorCaching, and results
+// are cached for at least d, overriding shorter expirations but not WithMaxTTL. Delete and
+// other explicit invalidations still let the key be recomputed right away.
+var WithMinRecomputeInterval = func(d time.Duration) Option {
+	return &MinRecomputeIntervalOption{Interval: d}
+}
+
+// HedgingOption is a struct that implements the Option interface.
+// It holds the delay after which a hedged computation starts a second attempt.
+type HedgingOption struct {
+	Delay time.Duration
+}
+
+// WithHedging returns an Option that reduces the tail latency of a flaky backend: if the memoized
+// function has not returned after delay, it is called a second time, concurrently, and the first
+// of the two calls to succeed provides the result. The error of a call is only returned if the
+// other one fails too. The losing call keeps running until it returns, and its result is
+// discarded, so the function must tolerate being called twice. WithTimeout bounds both calls
+// together.
+var WithHedging = func(delay time.Duration) Option {
+	return &HedgingOption{Delay: delay}
+}
+
+// KeyFuncOption is a struct that implements the Option interface.
+// It holds the function deriving the key of each argument of a function memoized by MemoizeFn1,
+// MemoizeFn2 or MemoizeFn3.
+type KeyFuncOption struct {
+	KeyFunc func(arg interface{}) string
+}
+
+// WithKeyFunc returns an Option for MemoizeFn1, MemoizeFn2 and MemoizeFn3 that derives the key
+// of each argument with keyFunc, instead of printing it with the %#v verb of package fmt.
+// Arguments with the same key share a cache entry. Other calls ignore it.
+//
+// Example usage:
+//
+//	search := memoizer.MemoizeFn1(results, runSearch, memoizer.WithKeyFunc(memoizer.KeyOf))
+var WithKeyFunc = func(keyFunc func(arg interface{}) string) Option {
+	return &KeyFuncOption{KeyFunc: keyFunc}
+}
+
+// BackendOption is a struct that implements the Option interface.
+// It holds the Store that a single call reads and writes instead of the Memoizer's store.
+type BackendOption struct {
+	Store Store
+}
+
+// WithBackend returns an Option that redirects the cache reads and writes of a single call to
+// store, such as a request-scoped cache layered over a shared Memoizer, while the Memoizer's own
+// store serves every other call. It is an escape hatch with consistency caveats:
+//
+//   - Only the expiration options apply to the entry stored in store. The call bypasses the
+//     Memoizer's bookkeeping, so it is not counted in Stats, not seen by hooks, and the entry
+//     cannot be tagged, peeked at, or removed with Delete, Flush or a Broadcaster.
+//   - The call still shares its computation with concurrent calls of the same key, with or
+//     without WithBackend. The result is then stored only where the caller that started the
+//     computation stores it, so either store may miss it.
+var WithBackend = func(store Store) Option {
+	return &BackendOption{Store: store}
+}
+
+// callOptions is the resolved form of the Options passed to a single Memoize call.
+type callOptions[T any] struct {
+	expiration          func(result T) time.Duration
+	admissionThreshold  int
+	contextKeyAugmenter func(ctx context.Context, key string) string
+	detachedContext     bool
+	eagerBackgroundFill bool
+	minTTL              time.Duration
+	maxTTL              time.Duration
+	panicFallback       func() (T, error)
+	cachePanicFallback  bool
+	auditLog            func(entry AuditEntry)
+	auditSummarize      func(result interface{}) string
+	afterCompute        func(key string, value T, set func(k string, v T, opts ...Option))
+	resultAttempts      int
+	resultValid         func(result T) bool
+	staleFor            time.Duration
+	staleOnErrorFor     time.Duration
+	reportStaleOnError  bool
+	errorTTL            func(err error) time.Duration
+	tags                []string
+	forceRefresh        bool
+	timeout             time.Duration
+	cacheLate           bool
+	retryAttempts       int
+	retryBackoff        BackoffFunc
+	retryable           func(err error) bool
+	fallback            func(key string, err error) (T, error)
+	cacheFallback       bool
+	autoRefresh         time.Duration
+	earlyBeta           float64
+	ttlJitter           float64
+	sliding             bool
+	cachePredicate      func(result T) bool
+	panicAsError        bool
+	maxWaiters          int
+	minRecompute        time.Duration
+	hedgeDelay          time.Duration
+	backend             Store
+	// invalid is the error from validating the options, if the Memoizer validates them.
+	invalid error
+}
+
+// resolve folds the default call options of the Memoizer and the options of a call into
+// callOptions, letting the call's options take precedence.
+func (m *Memoizer[T]) resolve(options []Option) callOptions[T] {
+	if len(m.defaultOptions) == 0 && !m.validateOptions {
+		return resolveOptions[T](options)
+	}
+	all := make([]Option, 0, len(m.defaultOptions)+len(options))
+	all = append(all, m.defaultOptions...)
+	opts := resolveOptions[T](append(all, options...))
+	if m.validateOptions {
+		// The defaults are validated apart from the call's options, which may override them.
+		opts.invalid = errors.Join(validateOptions[T](m.defaultOptions), validateOptions[T](options))
+	}
+	return opts
+}
+
+// resolveOptions folds a list of Options into callOptions. Later options take
+// precedence over earlier ones of the same kind.
+func resolveOptions[T any](options []Option) callOptions[T] {
+	var opts callOptions[T]
+	for _, option := range options {
+		switch opt := option.(type) {
+		case *ExpirationOption:
+			callback := opt.Callback
+			opts.expiration = func(result T) time.Duration { return callback(result) }
+		case *ExpirationForOption[T]:
+			opts.expiration = opt.Callback
+		case *TTLOption:
+			ttl := opt.TTL
+			opts.expiration = func(T) time.Duration { return ttl }
+			if ttl == 0 {
+				opts.expiration = nil
+			}
+		case *AdmissionThresholdOption:
+			opts.admissionThreshold = opt.Threshold
+		case *ContextKeyAugmenterOption:
+			opts.contextKeyAugmenter = opt.Augment
+		case *DetachedContextOption:
+			opts.detachedContext = true
+		case *EagerBackgroundFillOption:
+			opts.eagerBackgroundFill = true
+		case *TTLBoundsOption:
+			if opt.Min != 0 {
+				opts.minTTL = opt.Min
+			}
+			if opt.Max != 0 {
+				opts.maxTTL = opt.Max
+			}
+		case *PanicFallbackOption[T]:
+			opts.panicFallback = opt.Fallback
+			opts.cachePanicFallback = opt.Cache
+		case *AuditLogOption:
+			opts.auditLog = opt.Log
+			opts.auditSummarize = opt.Summarize
+		case *AfterComputeOption[T]:
+			opts.afterCompute = opt.Hook
+		case *StaleWhileRevalidateOption:
+			opts.staleFor = opt.StaleFor
+		case *SoftHardTTLOption:
+			soft := opt.Soft
+			opts.expiration = func(T) time.Duration { return soft }
+			opts.staleFor = opt.Hard - opt.Soft
+		case *ServeStaleOnErrorOption:
+			opts.staleOnErrorFor = opt.RetainFor
+			opts.reportStaleOnError = opt.Report
+		case *ErrorCachingOption:
+			opts.errorTTL = opt.TTL
+		case *TagsOption:
+			opts.tags = opt.Tags
+		case *ForceRefreshOption:
+			opts.forceRefresh = true
+		case *TimeoutOption:
+			opts.timeout = opt.Timeout
+			opts.cacheLate = opt.CacheLate
+		case *RetryOption:
+			opts.retryAttempts = opt.Attempts
+			opts.retryBackoff = opt.Backoff
+			opts.retryable = opt.Retryable
+		case *FallbackOption[T]:
+			opts.fallback = opt.Fallback
+			opts.cacheFallback = opt.Cache
+		case *AutoRefreshOption:
+			opts.autoRefresh = opt.Interval
+		case *EarlyExpirationOption:
+			opts.earlyBeta = opt.Beta
+		case *TTLJitterOption:
+			opts.ttlJitter = opt.Fraction
+		case *SlidingExpirationOption:
+			opts.sliding = true
+		case *CachePredicateOption[T]:
+			opts.cachePredicate = opt.Cacheable
+		case *PanicAsErrorOption:
+			opts.panicAsError = true
+		case *MaxWaitersOption:
+			opts.maxWaiters = opt.MaxWaiters
+		case *MinRecomputeIntervalOption:
+			opts.minRecompute = opt.Interval
+		case *HedgingOption:
+			opts.hedgeDelay = opt.Delay
+		case *ResultRetryOption[T]:
+			opts.resultAttempts = opt.MaxAttempts
+			opts.resultValid = opt.Valid
+		case *BackendOption:
+			opts.backend = opt.Store
+		}
+	}
+	return opts
+}
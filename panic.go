@@ -0,0 +1,51 @@
+package memoizer
+
+import "fmt"
+
+// PanicError is returned in place of a panic raised by a memoized function when the panic
+// cannot be propagated to the caller's goroutine, such as with MemoizeDoChan, or when
+// WithPanicAsError asks for it.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("memoized function panicked: %v\n\n%s", p.Value, p.Stack)
+}
+
+// Unwrap returns the panic value if it is an error, so that errors.Is and errors.As
+// can look through a PanicError.
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// recoveredPanic carries a panic recovered inside a singleflight computation back to the
+// callers, which decide whether to re-panic or report it as a PanicError.
+type recoveredPanic struct {
+	value interface{}
+	stack []byte
+}
+
+// Error implements the error interface.
+func (p *recoveredPanic) Error() string {
+	return fmt.Sprintf("%v", p.value)
+}
+
+// repanic propagates the panic carried by err, if any, to the caller: it re-panics with the
+// original value, or returns a *PanicError with WithPanicAsError. Other errors are returned as
+// they are.
+func repanic[T any](err error, opts callOptions[T]) error {
+	p, ok := err.(*recoveredPanic)
+	if !ok {
+		return err
+	}
+	if opts.panicAsError {
+		return &PanicError{Value: p.value, Stack: p.stack}
+	}
+	panic(p.value)
+}
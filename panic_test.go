@@ -0,0 +1,42 @@
+package memoizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanicAsError(t *testing.T) {
+	m := NewMemoizer[int]()
+
+	_, err := m.Memoize("key", func() (int, error) {
+		panic("boom")
+	}, WithPanicAsError())
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Contains(t, string(panicErr.Stack), "panic_test.go")
+
+	// Nothing is cached for the key.
+	value, err := m.Memoize("key", func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
+
+func TestPanicAsErrorUnwrapsErrors(t *testing.T) {
+	m := NewMemoizer[int]()
+	failure := errors.New("failure")
+
+	_, err := m.MemoizeContext(context.Background(), "key", func(ctx context.Context) (int, error) {
+		panic(failure)
+	}, WithPanicAsError())
+	assert.ErrorIs(t, err, failure)
+
+	_, err = m.MemoizeMulti("primary", func() (map[string]int, error) {
+		panic(failure)
+	}, WithPanicAsError())
+	assert.ErrorIs(t, err, failure)
+}
@@ -0,0 +1,43 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerPeek(t *testing.T) {
+	memoizer := NewMemoizerWithCacheExpiration[int](time.Minute)
+
+	_, ok := memoizer.Peek("key")
+	assert.False(t, ok)
+	assert.Empty(t, memoizer.Inventory(), "Peek should not compute anything")
+
+	_, err := memoizer.Memoize("key", func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	_, expiresAt, _ := memoizer.store.(InspectableStore).GetWithExpiration("key")
+
+	value, ok := memoizer.Peek("key")
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+
+	_, peekedExpiresAt, _ := memoizer.store.(InspectableStore).GetWithExpiration("key")
+	assert.Equal(t, expiresAt, peekedExpiresAt)
+	assert.Equal(t, Stats{Misses: 1, Entries: 1}, memoizer.Stats())
+}
+
+func TestMemoizerPeekExpiredRetainedEntry(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	_, err := memoizer.Memoize("key", func() (int, error) { return 42, nil }, WithExpiration(func(interface{}) time.Duration {
+		return 10 * time.Millisecond
+	}), WithServeStaleOnError(time.Minute))
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok := memoizer.Peek("key")
+	assert.False(t, ok, "an entry retained only as an error fallback should not be served")
+	assert.Zero(t, memoizer.Snapshot().Len(), "nor captured by a Snapshot")
+	assert.Zero(t, memoizer.Stats().Entries, "nor counted as an entry")
+}
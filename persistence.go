@@ -0,0 +1,123 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// persistence periodically writes a Memoizer's cache to a file, as configured by
+// WithPersistence.
+type persistence struct {
+	path     string
+	interval time.Duration
+	codec    Codec
+
+	mu       sync.Mutex // serializes writes to the file
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// startPersistence loads the cache from the persistence file, if it exists, and starts writing
+// it back every interval. A file that cannot be loaded is logged and otherwise ignored, so that
+// a corrupt file degrades to a cold start.
+func (m *Memoizer[T]) startPersistence(opt *PersistenceOption) {
+	p := &persistence{
+		path:     opt.Path,
+		interval: opt.Interval,
+		codec:    opt.Codec,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if p.codec == nil {
+		p.codec = m.codec
+	}
+	m.persistence = p
+
+	if data, err := os.ReadFile(p.path); err == nil {
+		err = m.restore(data, p.codec)
+		if err != nil && m.logger != nil {
+			m.logger.Warn("memoizer failed to load persisted cache", "path", p.path, "error", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) && m.logger != nil {
+		m.logger.Warn("memoizer failed to read persisted cache", "path", p.path, "error", err)
+	}
+
+	if p.interval <= 0 {
+		close(p.done)
+		return
+	}
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.Persist(); err != nil && m.logger != nil {
+					m.logger.Warn("memoizer failed to persist cache", "path", p.path, "error", err)
+				}
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopPersistence stops the periodic writes, waiting for one in progress to finish.
+func (m *Memoizer[T]) stopPersistence() {
+	if m.persistence == nil {
+		return
+	}
+	m.persistence.stopOnce.Do(func() {
+		close(m.persistence.stop)
+	})
+	<-m.persistence.done
+}
+
+// Persist writes the cache to the file configured by WithPersistence right away, such as on
+// graceful shutdown. The file is replaced atomically, so a crash mid-write leaves the previous
+// version intact. Persist does nothing if persistence is not configured.
+func (m *Memoizer[T]) Persist() error {
+	p := m.persistence
+	if p == nil {
+		return nil
+	}
+	data, err := m.export(p.codec)
+	if err != nil {
+		return fmt.Errorf("memoizer: persist: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := writeFileAtomic(p.path, data); err != nil {
+		return fmt.Errorf("memoizer: persist: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it over path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly once the file has been renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,50 @@
+package memoizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	memoizer := NewMemoizerWithOptions[int](WithPersistence(path, 10*time.Millisecond, nil))
+	t.Cleanup(memoizer.stopPersistence)
+	memoizer.Set("a", 1, time.Minute)
+	memoizer.Set("b", 2, NoExpiration)
+
+	assert.Eventually(t, func() bool {
+		restarted := NewMemoizerWithOptions[int](WithPersistence(path, 0, nil))
+		return restarted.Snapshot().Len() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	restarted := NewMemoizerWithOptions[int](WithPersistence(path, 0, GobCodec{}))
+	assert.Zero(t, restarted.Snapshot().Len(), "a file in another format should be ignored")
+	assert.Equal(t, path, memoizer.Config().PersistencePath)
+	assert.Equal(t, 10*time.Millisecond, memoizer.Config().PersistenceInterval)
+}
+
+func TestMemoizerPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.gob")
+
+	memoizer := NewMemoizerWithOptions[string](WithPersistence(path, 0, GobCodec{}))
+	memoizer.Set("key", "value", NoExpiration)
+	require.NoError(t, memoizer.Persist())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files should be left behind")
+
+	restarted := NewMemoizerWithOptions[string](WithPersistence(path, 0, GobCodec{}))
+	value, ok := restarted.Peek("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+
+	// Without persistence, Persist does nothing.
+	assert.NoError(t, NewMemoizer[int]().Persist())
+}
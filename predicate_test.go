@@ -0,0 +1,39 @@
+package memoizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachePredicateSkipsUncacheableResults(t *testing.T) {
+	m := NewMemoizer[[]int]()
+	nonEmpty := WithCachePredicate(func(result []int) bool {
+		return len(result) > 0
+	})
+
+	calls := 0
+	fn := func() ([]int, error) {
+		calls++
+		if calls == 1 {
+			return nil, nil
+		}
+		return []int{1, 2}, nil
+	}
+
+	result, err := m.Memoize("key", fn, nonEmpty)
+	require.NoError(t, err)
+	assert.Empty(t, result, "an uncacheable result is still returned")
+	_, ok := m.Peek("key")
+	assert.False(t, ok)
+
+	result, err = m.Memoize("key", fn, nonEmpty)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, result)
+
+	result, err = m.Memoize("key", fn, nonEmpty)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, result)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,60 @@
+package memoizer
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinRecomputeIntervalReplaysErrors(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	failure := errors.New("backend down")
+	fn := func() (int, error) {
+		if calls.Add(1) == 1 {
+			return 0, failure
+		}
+		return 42, nil
+	}
+	interval := WithMinRecomputeInterval(50 * time.Millisecond)
+
+	_, err := m.Memoize("key", fn, interval)
+	require.ErrorIs(t, err, failure)
+	for i := 0; i < 3; i++ {
+		_, err = m.Memoize("key", fn, interval)
+		assert.ErrorIs(t, err, failure, "the last error should be replayed within the interval")
+	}
+	assert.EqualValues(t, 1, calls.Load())
+
+	time.Sleep(60 * time.Millisecond)
+	value, err := m.Memoize("key", fn, interval)
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestMinRecomputeIntervalExtendsShortExpirations(t *testing.T) {
+	m := NewMemoizerWithCacheExpiration[int](time.Millisecond)
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		return int(calls.Add(1)), nil
+	}
+	interval := WithMinRecomputeInterval(50 * time.Millisecond)
+
+	_, err := m.Memoize("key", fn, interval)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	value, err := m.Memoize("key", fn, interval)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	// Explicit invalidation still recomputes right away.
+	m.Delete("key")
+	value, err = m.Memoize("key", fn, interval)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+}
@@ -0,0 +1,102 @@
+package memoizer
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// RecordedOp is a single cache operation captured by a Recorder.
+type RecordedOp struct {
+	Time time.Time
+	Key  string
+	Hit  bool
+	// ComputeDuration is how long the computation took on a miss.
+	ComputeDuration time.Duration
+}
+
+// Recorder captures the sequence of cache operations performed by a Memoizer, so the same
+// workload can later be replayed against differently configured Memoizers. A Recorder is
+// attached with WithRecorder and is safe for concurrent use.
+//
+// Hits are recorded per call. Misses are recorded once per computation: callers that join an
+// in-flight computation are not recorded separately.
+type Recorder struct {
+	mu  sync.Mutex
+	ops []RecordedOp
+}
+
+// NewRecorder creates and returns a new, empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// record appends an operation to the recording.
+func (r *Recorder) record(op RecordedOp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops = append(r.ops, op)
+}
+
+// Operations returns a copy of the recorded operations, ordered by time.
+func (r *Recorder) Operations() []RecordedOp {
+	r.mu.Lock()
+	ops := make([]RecordedOp, len(r.ops))
+	copy(ops, r.ops)
+	r.mu.Unlock()
+
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].Time.Before(ops[j].Time)
+	})
+	return ops
+}
+
+// ReplayResult summarizes the outcome of a Replay.
+type ReplayResult struct {
+	Hits        int
+	Misses      int
+	ComputeTime time.Duration
+}
+
+// Replay drives m with the recorded operations, reproducing the recorded gaps between them
+// and replacing each computation with a synthetic one that sleeps for the recorded duration
+// (or the average recorded compute duration of its key, for operations that were hits in the
+// recording). Replaying the same recording against Memoizers with different TTLs or options
+// gives an apples-to-apples comparison of their hit rates. Replay runs in real time.
+func Replay(m *Memoizer[struct{}], ops []RecordedOp, options ...Option) ReplayResult {
+	durations := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, op := range ops {
+		if !op.Hit {
+			durations[op.Key] += op.ComputeDuration
+			counts[op.Key]++
+		}
+	}
+
+	var result ReplayResult
+	for i, op := range ops {
+		if i > 0 {
+			time.Sleep(op.Time.Sub(ops[i-1].Time))
+		}
+
+		duration := op.ComputeDuration
+		if op.Hit && counts[op.Key] > 0 {
+			duration = durations[op.Key] / time.Duration(counts[op.Key])
+		}
+
+		computed := false
+		_, _ = m.Memoize(op.Key, func() (struct{}, error) {
+			computed = true
+			time.Sleep(duration)
+			return struct{}{}, nil
+		}, options...)
+
+		if computed {
+			result.Misses++
+			result.ComputeTime += duration
+		} else {
+			result.Hits++
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	memoizer := NewMemoizerWithOptions[int](WithRecorder(recorder))
+
+	compute := func() (int, error) {
+		time.Sleep(5 * time.Millisecond)
+		return 42, nil
+	}
+	for _, key := range []string{"a", "a", "b", "a"} {
+		_, err := memoizer.Memoize(key, compute)
+		require.NoError(t, err)
+	}
+
+	ops := recorder.Operations()
+	require.Len(t, ops, 4)
+	assert.Equal(t, []string{"a", "a", "b", "a"}, []string{ops[0].Key, ops[1].Key, ops[2].Key, ops[3].Key})
+	assert.Equal(t, []bool{false, true, false, true}, []bool{ops[0].Hit, ops[1].Hit, ops[2].Hit, ops[3].Hit})
+	assert.GreaterOrEqual(t, ops[0].ComputeDuration, 5*time.Millisecond)
+	assert.Zero(t, ops[1].ComputeDuration)
+}
+
+func TestReplay(t *testing.T) {
+	start := time.Now()
+	ops := []RecordedOp{
+		{Time: start, Key: "a", ComputeDuration: time.Millisecond},
+		{Time: start.Add(10 * time.Millisecond), Key: "a", Hit: true},
+		{Time: start.Add(20 * time.Millisecond), Key: "b", ComputeDuration: time.Millisecond},
+		{Time: start.Add(60 * time.Millisecond), Key: "a", Hit: true},
+	}
+
+	// With no expiration, the recorded workload replays with the same hit pattern.
+	result := Replay(NewMemoizer[struct{}](), ops)
+	assert.Equal(t, ReplayResult{Hits: 2, Misses: 2, ComputeTime: 2 * time.Millisecond}, result)
+
+	// With a short TTL, the last access to "a" misses and is recomputed.
+	result = Replay(NewMemoizerWithCacheExpiration[struct{}](30*time.Millisecond), ops)
+	assert.Equal(t, ReplayResult{Hits: 1, Misses: 3, ComputeTime: 3 * time.Millisecond}, result)
+}
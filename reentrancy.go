@@ -0,0 +1,127 @@
+package memoizer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// ErrReentrantComputation is returned when a memoized function, directly or indirectly,
+// requests its own key on the same goroutine, or through the context it was given by
+// MemoizeContext. Waiting on the in-flight computation would otherwise deadlock, since that
+// computation is waiting on the caller.
+var ErrReentrantComputation = errors.New("reentrant computation of the same key")
+
+// ErrMaxRecursionDepth is returned when nested computations exceed the depth configured with
+// WithMaxRecursionDepth.
+var ErrMaxRecursionDepth = errors.New("maximum recursion depth exceeded")
+
+// reentrancyGuard tracks which goroutine is computing each key, and how deeply computations
+// are nested on each goroutine, for the calls that carry no context, such as Memoize.
+type reentrancyGuard struct {
+	maxDepth int
+
+	mu        sync.Mutex
+	computing map[string]uint64
+	depth     map[uint64]int
+}
+
+// reentrant reports whether key is being computed by the current goroutine.
+func (g *reentrancyGuard) reentrant(key string) bool {
+	g.mu.Lock()
+	owner, ok := g.computing[key]
+	g.mu.Unlock()
+	// Only pay for looking up the goroutine ID when the key is actually in flight.
+	return ok && owner == goroutineID()
+}
+
+// enter marks key as being computed by the goroutine gid. It fails if this would exceed the
+// maximum recursion depth.
+func (g *reentrancyGuard) enter(key string, gid uint64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.computing == nil {
+		g.computing = make(map[string]uint64)
+		g.depth = make(map[uint64]int)
+	}
+	if g.maxDepth > 0 && g.depth[gid] >= g.maxDepth {
+		return ErrMaxRecursionDepth
+	}
+	g.computing[key] = gid
+	g.depth[gid]++
+	return nil
+}
+
+// exit undoes a successful enter.
+func (g *reentrancyGuard) exit(key string, gid uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.computing, key)
+	if g.depth[gid]--; g.depth[gid] <= 0 {
+		delete(g.depth, gid)
+	}
+}
+
+// goroutineID returns the ID of the current goroutine, parsed from its stack header
+// ("goroutine 123 [running]:"). The runtime does not expose it otherwise.
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	header := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
+
+// computingContextKey is the context key under which MemoizeContext passes the computations
+// in progress to the function it memoizes.
+type computingContextKey struct{}
+
+// computing is a computation in progress, linked to the computation it is nested in. Carrying
+// the chain in the context, unlike reentrancyGuard, follows the computation onto the goroutines
+// of WithTimeout and WithHedging.
+type computing struct {
+	owner  interface{} // the Memoizer computing key, since keys of different Memoizers differ
+	key    string
+	parent *computing
+}
+
+// computingFrom returns the innermost computation in progress recorded in ctx, if any.
+func computingFrom(ctx context.Context) *computing {
+	c, _ := ctx.Value(computingContextKey{}).(*computing)
+	return c
+}
+
+// withComputing returns a copy of ctx recording that owner is computing key within the
+// computations already recorded in it.
+func withComputing(ctx context.Context, owner interface{}, key string) context.Context {
+	return context.WithValue(ctx, computingContextKey{}, &computing{owner: owner, key: key, parent: computingFrom(ctx)})
+}
+
+// includes reports whether owner is computing key in the chain of c.
+func (c *computing) includes(owner interface{}, key string) bool {
+	for ; c != nil; c = c.parent {
+		if c.owner == owner && c.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// depth returns the number of computations of owner in the chain of c.
+func (c *computing) depth(owner interface{}) int {
+	depth := 0
+	for ; c != nil; c = c.parent {
+		if c.owner == owner {
+			depth++
+		}
+	}
+	return depth
+}
@@ -0,0 +1,154 @@
+package memoizer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReentrantComputation(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	var compute func(ctx context.Context) (int, error)
+	compute = func(ctx context.Context) (int, error) {
+		// A buggy recursion that requests its own key.
+		return memoizer.MemoizeContext(ctx, "self", compute)
+	}
+
+	_, err := memoizer.MemoizeContext(context.Background(), "self", compute)
+	require.ErrorIs(t, err, ErrReentrantComputation)
+
+	// The key is not left marked as in flight.
+	result, err := memoizer.Memoize("self", func() (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestReentrantMemoize(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	var compute func() (int, error)
+	compute = func() (int, error) {
+		// A buggy recursion that requests its own key, without a context to follow.
+		return memoizer.Memoize("self", compute)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := memoizer.Memoize("self", compute)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, ErrReentrantComputation)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the reentrant computation deadlocked")
+	}
+
+	result, err := memoizer.Memoize("self", func() (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestReentrantComputationOnAnotherGoroutine(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	// With WithTimeout, the function runs on a goroutine of its own.
+	var compute func(ctx context.Context) (int, error)
+	compute = func(ctx context.Context) (int, error) {
+		return memoizer.MemoizeContext(ctx, "self", compute, WithTimeout(time.Minute))
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := memoizer.MemoizeContext(context.Background(), "self", compute, WithTimeout(time.Minute))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, ErrReentrantComputation)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the reentrant computation deadlocked")
+	}
+}
+
+func TestReentrancyIsPerMemoizer(t *testing.T) {
+	outer, inner := NewMemoizer[int](), NewMemoizer[int]()
+
+	result, err := outer.MemoizeContext(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return inner.MemoizeContext(ctx, "key", func(context.Context) (int, error) { return 42, nil })
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestRecursiveMemoization(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	var fib func(n int) (int, error)
+	fib = func(n int) (int, error) {
+		return memoizer.Memoize(fmt.Sprintf("fib(%d)", n), func() (int, error) {
+			if n < 2 {
+				return n, nil
+			}
+			a, err := fib(n - 1)
+			if err != nil {
+				return 0, err
+			}
+			b, err := fib(n - 2)
+			return a + b, err
+		})
+	}
+
+	result, err := fib(30)
+	require.NoError(t, err)
+	assert.Equal(t, 832040, result)
+}
+
+func TestMemoizerWithMaxRecursionDepth(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithMaxRecursionDepth(10))
+
+	var depth func(ctx context.Context, n int) (int, error)
+	depth = func(ctx context.Context, n int) (int, error) {
+		return memoizer.MemoizeContext(ctx, fmt.Sprintf("depth(%d)", n), func(ctx context.Context) (int, error) {
+			if n == 0 {
+				return 0, nil
+			}
+			return depth(ctx, n-1)
+		})
+	}
+
+	_, err := depth(context.Background(), 5)
+	require.NoError(t, err)
+
+	_, err = depth(context.Background(), 20)
+	require.ErrorIs(t, err, ErrMaxRecursionDepth)
+}
+
+func TestMemoizeWithMaxRecursionDepth(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithMaxRecursionDepth(10))
+
+	var depth func(n int) (int, error)
+	depth = func(n int) (int, error) {
+		return memoizer.Memoize(fmt.Sprintf("depth(%d)", n), func() (int, error) {
+			if n == 0 {
+				return 0, nil
+			}
+			return depth(n - 1)
+		})
+	}
+
+	_, err := depth(5)
+	require.NoError(t, err)
+
+	_, err = depth(20)
+	require.ErrorIs(t, err, ErrMaxRecursionDepth)
+}
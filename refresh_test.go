@@ -0,0 +1,61 @@
+package memoizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithForceRefresh(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	var calls int
+	fn := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	result, err := memoizer.Memoize("key", fn)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	result, err = memoizer.Memoize("key", fn, WithForceRefresh())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result)
+
+	// The refreshed value replaced the entry.
+	result, err = memoizer.Memoize("key", fn)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result)
+
+	result, err = memoizer.MemoizeContext(context.Background(), "key", func(context.Context) (int, error) {
+		return fn()
+	}, WithForceRefresh())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result)
+}
+
+func TestMemoizerWithForceRefreshBypassesCachedError(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	_, err := memoizer.Memoize("key", func() (int, error) { return 0, errors.New("failed") }, WithErrorCaching(time.Minute))
+	require.Error(t, err)
+
+	result, err := memoizer.Memoize("key", func() (int, error) { return 42, nil }, WithForceRefresh())
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestMemoizerWithForceRefreshFailureKeepsEntry(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	memoizer.SetAll(map[string]int{"key": 1})
+
+	_, err := memoizer.Memoize("key", func() (int, error) { return 0, errors.New("failed") }, WithForceRefresh())
+	require.Error(t, err)
+
+	result, err := memoizer.Memoize("key", func() (int, error) { return 2, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+}
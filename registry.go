@@ -0,0 +1,212 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrAlreadyRegistered is returned by Register for a name that is already taken.
+var ErrAlreadyRegistered = errors.New("memoizer already registered")
+
+// Registry owns a set of named Memoizers, possibly of different types, such as the caches of
+// a service, and bounds their entries as a whole rather than one by one: they share a single
+// LRUStore, so the least recently used entries are evicted across all of them. It also reports
+// their combined statistics and flushes them all at once, such as on a configuration reload.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	store *LRUStore
+
+	mu      sync.Mutex
+	members map[string]registryMember
+
+	// evictionsMu guards evictions apart from mu, which Register holds while a new Memoizer
+	// registers its eviction callback.
+	evictionsMu sync.Mutex
+	evictions   map[string]func(key string, value interface{})
+}
+
+// registryMember is the part of a Memoizer a Registry uses, whatever its type.
+type registryMember interface {
+	Stats() Stats
+	Flush()
+}
+
+// NewRegistry creates and returns a new Registry. It accepts WithMaxEntries and WithMaxCost,
+// which bound the entries of all its Memoizers together; without them, it bounds nothing.
+//
+// Example usage:
+//
+//	registry := memoizer.NewRegistry(memoizer.WithMaxEntries(100000))
+//	users, err := memoizer.Register[User](registry, "users")
+//	orders, err := memoizer.Register[[]Order](registry, "orders", memoizer.WithDefaultExpiration(time.Minute))
+func NewRegistry(options ...Option) *Registry {
+	var (
+		maxEntries int
+		maxCost    int64
+		cost       func(value interface{}) int64
+	)
+	for _, option := range options {
+		switch opt := option.(type) {
+		case *MaxEntriesOption:
+			maxEntries = opt.MaxEntries
+		case *MaxCostOption:
+			maxCost, cost = opt.MaxCost, opt.Cost
+		}
+	}
+	r := &Registry{
+		store:     NewLRUStoreWithCost(maxEntries, maxCost, cost),
+		members:   make(map[string]registryMember),
+		evictions: make(map[string]func(key string, value interface{})),
+	}
+	r.store.OnEvicted(r.evicted)
+	return r
+}
+
+// Register creates a Memoizer with New and the given options, and adds it to r under name. It
+// stores its entries in the store shared by the Registry, so WithStore, WithMaxEntries,
+// WithMaxCost and WithCleanupInterval are ignored. Register returns an error wrapping
+// ErrAlreadyRegistered if name is taken.
+//
+// Register is a function rather than a method because Go does not allow methods to have type
+// parameters of their own.
+func Register[T any](r *Registry, name string, options ...Option) (*Memoizer[T], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.members[name]; ok {
+		return nil, fmt.Errorf("%w: %q", ErrAlreadyRegistered, name)
+	}
+
+	view := &registryStore{registry: r, prefix: name + "\x00"}
+	all := make([]Option, 0, len(options)+1)
+	all = append(all, options...)
+	m := New[T](append(all, WithStore(view))...)
+	r.members[name] = m
+	return m, nil
+}
+
+// Names returns the names of the Memoizers of r, in increasing order.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(r.members))
+	for name := range r.members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stats returns the statistics of the Memoizers of r added together.
+func (r *Registry) Stats() Stats {
+	var total Stats
+	for _, stats := range r.StatsByName() {
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Evictions += stats.Evictions
+		total.Entries += stats.Entries
+		total.InFlight += stats.InFlight
+	}
+	return total
+}
+
+// StatsByName returns the statistics of each Memoizer of r, by name.
+func (r *Registry) StatsByName() map[string]Stats {
+	r.mu.Lock()
+	members := make(map[string]registryMember, len(r.members))
+	for name, member := range r.members {
+		members[name] = member
+	}
+	r.mu.Unlock()
+
+	stats := make(map[string]Stats, len(members))
+	for name, member := range members {
+		stats[name] = member.Stats()
+	}
+	return stats
+}
+
+// Flush flushes every Memoizer of r, as Memoizer.Flush does.
+func (r *Registry) Flush() {
+	r.mu.Lock()
+	members := make([]registryMember, 0, len(r.members))
+	for _, member := range r.members {
+		members = append(members, member)
+	}
+	r.mu.Unlock()
+
+	for _, member := range members {
+		member.Flush()
+	}
+}
+
+// evicted reports an entry removed from the shared store to the Memoizer it belongs to.
+func (r *Registry) evicted(key string, value interface{}) {
+	name, key, ok := strings.Cut(key, "\x00")
+	if !ok {
+		return
+	}
+	r.evictionsMu.Lock()
+	fn := r.evictions[name]
+	r.evictionsMu.Unlock()
+	if fn != nil {
+		fn(key, value)
+	}
+}
+
+// registryStore is the Store of a Memoizer of a Registry: the entries of the shared store
+// whose keys start with its prefix, the Memoizer's name followed by a NUL byte.
+type registryStore struct {
+	registry *Registry
+	prefix   string
+}
+
+func (s *registryStore) Get(key string) (interface{}, bool) {
+	return s.registry.store.Get(s.prefix + key)
+}
+
+func (s *registryStore) GetWithExpiration(key string) (interface{}, time.Time, bool) {
+	return s.registry.store.GetWithExpiration(s.prefix + key)
+}
+
+func (s *registryStore) Set(key string, value interface{}, ttl time.Duration) {
+	s.registry.store.Set(s.prefix+key, value, ttl)
+}
+
+func (s *registryStore) Delete(key string) {
+	s.registry.store.Delete(s.prefix + key)
+}
+
+// Flush removes the entries of this store only.
+func (s *registryStore) Flush() {
+	s.registry.store.flushMatch(func(key string) bool {
+		return strings.HasPrefix(key, s.prefix)
+	})
+}
+
+func (s *registryStore) Entries() map[string]StoreEntry {
+	entries := make(map[string]StoreEntry)
+	s.Range(func(key string, entry StoreEntry) bool {
+		entries[key] = entry
+		return true
+	})
+	return entries
+}
+
+func (s *registryStore) Range(fn func(key string, entry StoreEntry) bool) {
+	s.registry.store.Range(func(key string, entry StoreEntry) bool {
+		if key, ok := strings.CutPrefix(key, s.prefix); ok {
+			return fn(key, entry)
+		}
+		return true
+	})
+}
+
+func (s *registryStore) OnEvicted(fn func(key string, value interface{})) {
+	s.registry.evictionsMu.Lock()
+	defer s.registry.evictionsMu.Unlock()
+	s.registry.evictions[strings.TrimSuffix(s.prefix, "\x00")] = fn
+}
@@ -0,0 +1,54 @@
+package memoizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(WithMaxEntries(3))
+	numbers, err := Register[int](registry, "numbers")
+	require.NoError(t, err)
+	names, err := Register[string](registry, "names")
+	require.NoError(t, err)
+	_, err = Register[int](registry, "numbers")
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+	assert.Equal(t, []string{"names", "numbers"}, registry.Names())
+
+	// The same key in different Memoizers does not collide.
+	_, err = numbers.Memoize("a", func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	_, err = names.Memoize("a", func() (string, error) { return "one", nil })
+	require.NoError(t, err)
+	name, ok := names.Peek("a")
+	assert.True(t, ok)
+	assert.Equal(t, "one", name)
+
+	// The entry budget is shared: filling one Memoizer evicts the least recently used entry
+	// of the other.
+	for i := 0; i < 2; i++ {
+		i := i
+		_, err = names.Memoize(fmt.Sprint("name", i), func() (string, error) { return fmt.Sprint(i), nil })
+		require.NoError(t, err)
+	}
+	_, ok = numbers.Peek("a")
+	assert.False(t, ok)
+	assert.Equal(t, uint64(1), numbers.Stats().Evictions)
+
+	stats := registry.Stats()
+	assert.Equal(t, 3, stats.Entries)
+	assert.Equal(t, uint64(4), stats.Misses)
+	assert.Equal(t, 3, registry.StatsByName()["names"].Entries)
+
+	// Flushing one Memoizer leaves the others alone; flushing the registry clears them all.
+	_, err = numbers.Memoize("b", func() (int, error) { return 2, nil })
+	require.NoError(t, err)
+	names.Flush()
+	assert.Equal(t, 0, names.Stats().Entries)
+	assert.Equal(t, 1, numbers.Stats().Entries)
+	registry.Flush()
+	assert.Equal(t, 0, registry.Stats().Entries)
+}
@@ -0,0 +1,42 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRename(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	_, err := memoizer.Memoize("old", func() (int, error) {
+		return 42, nil
+	}, WithExpiration(func(interface{}) time.Duration {
+		return time.Minute
+	}))
+	require.NoError(t, err)
+	_, oldExpiresAt, _ := memoizer.store.(InspectableStore).GetWithExpiration("old")
+
+	assert.True(t, memoizer.Rename("old", "new"))
+
+	_, ok := memoizer.store.Get("old")
+	assert.False(t, ok, "the old key should be absent after a rename")
+
+	value, newExpiresAt, ok := memoizer.store.(InspectableStore).GetWithExpiration("new")
+	require.True(t, ok)
+	assert.Equal(t, 42, value)
+	assert.WithinDuration(t, oldExpiresAt, newExpiresAt, 10*time.Millisecond, "remaining TTL should carry over")
+
+	result, err := memoizer.Memoize("new", func() (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+
+	assert.False(t, memoizer.Rename("missing", "other"))
+	_, ok = memoizer.store.Get("other")
+	assert.False(t, ok)
+}
@@ -0,0 +1,54 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithResultRetry(t *testing.T) {
+	memoizer := NewMemoizer[[]string]()
+	callCount := 0
+	fn := func() ([]string, error) {
+		callCount++
+		if callCount < 3 {
+			return nil, nil // incomplete
+		}
+		return []string{"complete"}, nil
+	}
+	complete := func(result []string) bool {
+		return len(result) > 0
+	}
+
+	result, err := memoizer.Memoize("key", fn, WithResultRetry(5, complete))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"complete"}, result)
+	assert.Equal(t, 3, callCount)
+
+	result, err = memoizer.Memoize("key", func() ([]string, error) {
+		return nil, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"complete"}, result, "the validated result should be cached")
+}
+
+func TestMemoizerWithResultRetryExhausted(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	callCount := 0
+	fn := func() (int, error) {
+		callCount++
+		return callCount, nil
+	}
+
+	result, err := memoizer.Memoize("key", fn, WithResultRetry(3, func(result int) bool {
+		return result > 10
+	}))
+	require.ErrorIs(t, err, ErrResultInvalid)
+	assert.Equal(t, 3, result, "the last result should be returned")
+	assert.Equal(t, 3, callCount)
+
+	_, cached := memoizer.store.Get("key")
+	assert.False(t, cached, "an invalid result should not be cached")
+}
@@ -0,0 +1,37 @@
+package memoizer
+
+import "time"
+
+// BackoffFunc returns how long to wait before the given retry of a failed computation; attempt
+// is 1 before the first retry.
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff returns a BackoffFunc that waits base before the first retry and doubles
+// the wait before each following one, up to max.
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		wait := base
+		for i := 1; i < attempt && wait < max; i++ {
+			wait *= 2
+		}
+		if wait > max {
+			wait = max
+		}
+		return wait
+	}
+}
+
+// callRetrying runs fn like call, and re-runs it as allowed by WithRetry while it fails.
+func (m *Memoizer[T]) callRetrying(fn func() (T, error), opts callOptions[T]) (T, error, bool) {
+	res, err, fellBack := m.call(fn, opts)
+	for attempt := 1; attempt < opts.retryAttempts && err != nil && !fellBack; attempt++ {
+		if opts.retryable != nil && !opts.retryable(err) {
+			break
+		}
+		if opts.retryBackoff != nil {
+			time.Sleep(opts.retryBackoff(attempt))
+		}
+		res, err, fellBack = m.call(fn, opts)
+	}
+	return res, err, fellBack
+}
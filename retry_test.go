@@ -0,0 +1,86 @@
+package memoizer
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryRecoversFromTransientErrors(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	var waits []time.Duration
+	backoff := func(attempt int) time.Duration {
+		waits = append(waits, time.Duration(attempt)*time.Millisecond)
+		return time.Duration(attempt) * time.Millisecond
+	}
+
+	value, err := m.Memoize("key", func() (int, error) {
+		if calls.Add(1) < 3 {
+			return 0, errors.New("transient")
+		}
+		return 42, nil
+	}, WithRetry(5, backoff))
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+	assert.EqualValues(t, 3, calls.Load())
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, waits)
+}
+
+func TestRetryReturnsLastErrorToAllCallers(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	started := make(chan struct{})
+	var once sync.Once
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = m.Memoize("key", func() (int, error) {
+				once.Do(func() { close(started) })
+				n := calls.Add(1)
+				time.Sleep(10 * time.Millisecond)
+				return 0, errors.New("failure " + string(rune('0'+n)))
+			}, WithRetry(3, nil))
+		}(i)
+		if i == 0 {
+			<-started
+		}
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 3, calls.Load())
+	for _, err := range errs {
+		assert.EqualError(t, err, "failure 3")
+	}
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	m := NewMemoizer[int]()
+	permanent := errors.New("permanent")
+	var calls atomic.Int32
+
+	_, err := m.Memoize("key", func() (int, error) {
+		calls.Add(1)
+		return 0, permanent
+	}, &RetryOption{Attempts: 3, Retryable: func(err error) bool { return !errors.Is(err, permanent) }})
+	assert.ErrorIs(t, err, permanent)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, backoff(1))
+	assert.Equal(t, 20*time.Millisecond, backoff(2))
+	assert.Equal(t, 40*time.Millisecond, backoff(3))
+	assert.Equal(t, 50*time.Millisecond, backoff(4))
+	assert.Equal(t, 50*time.Millisecond, backoff(10))
+}
@@ -0,0 +1,13 @@
+package memoizer
+
+// acquireComputation waits for a slot to run a computation under
+// WithMaxConcurrentComputations, and returns the function that releases it.
+func (m *Memoizer[T]) acquireComputation() (release func()) {
+	if m.computations == nil {
+		return func() {}
+	}
+	m.computations <- struct{}{}
+	return func() {
+		<-m.computations
+	}
+}
@@ -0,0 +1,43 @@
+package memoizer
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxConcurrentComputations(t *testing.T) {
+	m := NewMemoizerWithOptions[int](WithMaxConcurrentComputations(2))
+	assert.Equal(t, 2, m.Config().MaxConcurrentComputations)
+
+	var running, peak atomic.Int32
+	fn := func() (int, error) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		return 1, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := m.Memoize(fmt.Sprint("key", i), fn)
+			require.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+	assert.EqualValues(t, 2, peak.Load())
+}
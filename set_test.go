@@ -0,0 +1,54 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerSet(t *testing.T) {
+	memoizer := NewMemoizerWithCacheExpiration[int](time.Hour)
+	store := memoizer.store.(InspectableStore)
+
+	memoizer.Set("short", 1, time.Minute)
+	memoizer.Set("default", 2, 0)
+	memoizer.Set("forever", 3, NoExpiration)
+
+	_, expiresAt, ok := store.GetWithExpiration("short")
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+	_, expiresAt, ok = store.GetWithExpiration("default")
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Second)
+	_, expiresAt, ok = store.GetWithExpiration("forever")
+	require.True(t, ok)
+	assert.True(t, expiresAt.IsZero())
+
+	result, err := memoizer.Memoize("short", func() (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	// Set replaces a cached entry.
+	memoizer.Set("short", 10, time.Minute)
+	value, ok := memoizer.Peek("short")
+	assert.True(t, ok)
+	assert.Equal(t, 10, value)
+}
+
+func TestMemoizerSetMany(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	memoizer.SetMany(map[string]string{"a": "A", "b": "B"}, time.Minute)
+
+	for key, want := range map[string]string{"a": "A", "b": "B"} {
+		value, ok := memoizer.Peek(key)
+		assert.True(t, ok)
+		assert.Equal(t, want, value)
+		_, expiresAt, _ := memoizer.store.(InspectableStore).GetWithExpiration(key)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+	}
+}
@@ -0,0 +1,68 @@
+package memoizer
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetAll(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	values := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		values["key"+strconv.Itoa(i)] = i
+	}
+
+	memoizer.SetAll(values, WithExpiration(func(interface{}) time.Duration {
+		return time.Minute
+	}))
+
+	for key, expected := range values {
+		result, err := memoizer.Memoize(key, func() (int, error) {
+			return 0, errors.New("this should not be called")
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+
+		_, expiresAt, _ := memoizer.store.(InspectableStore).GetWithExpiration(key)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+	}
+}
+
+func benchmarkValues() map[string]int {
+	values := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		values["key"+strconv.Itoa(i)] = i
+	}
+	return values
+}
+
+func BenchmarkSetAll(b *testing.B) {
+	values := benchmarkValues()
+	memoizer := NewMemoizer[int]()
+	expiration := WithExpiration(func(interface{}) time.Duration {
+		return time.Minute
+	})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		memoizer.SetAll(values, expiration)
+	}
+}
+
+func BenchmarkSetAllOneByOne(b *testing.B) {
+	values := benchmarkValues()
+	memoizer := NewMemoizer[int]()
+	expiration := WithExpiration(func(interface{}) time.Duration {
+		return time.Minute
+	})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for key, value := range values {
+			memoizer.SetAll(map[string]int{key: value}, expiration)
+		}
+	}
+}
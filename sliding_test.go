@@ -0,0 +1,63 @@
+package memoizer
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlidingExpirationKeepsReadEntries(t *testing.T) {
+	m := NewMemoizerWithCacheExpiration[int](40 * time.Millisecond)
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		return int(calls.Add(1)), nil
+	}
+
+	_, err := m.Memoize("key", fn, WithSlidingExpiration())
+	require.NoError(t, err)
+	deadline := time.Now().Add(120 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		value, err := m.Memoize("key", fn, WithSlidingExpiration())
+		require.NoError(t, err)
+		assert.Equal(t, 1, value)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.EqualValues(t, 1, calls.Load())
+
+	// Idle entries expire.
+	time.Sleep(60 * time.Millisecond)
+	_, ok := m.Peek("key")
+	assert.False(t, ok)
+}
+
+func TestSlidingExpirationIgnoresPeek(t *testing.T) {
+	m := NewMemoizerWithCacheExpiration[int](40 * time.Millisecond)
+	_, err := m.Memoize("key", func() (int, error) { return 1, nil }, WithSlidingExpiration())
+	require.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		m.Peek("key")
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, ok := m.Peek("key")
+	assert.False(t, ok)
+}
+
+func TestSlidingExpirationKeepsAccessStatistics(t *testing.T) {
+	m := NewMemoizerWithCacheExpiration[int](time.Minute)
+	fn := func() (int, error) { return 1, nil }
+	for i := 0; i < 3; i++ {
+		_, err := m.Memoize("key", fn, WithSlidingExpiration(), WithTags("tag"))
+		require.NoError(t, err)
+	}
+	_, err := m.Memoize("key", fn, WithSlidingExpiration())
+	require.NoError(t, err)
+
+	inventory := m.Inventory()
+	require.Len(t, inventory, 1)
+	assert.EqualValues(t, 3, inventory[0].Hits)
+	assert.Equal(t, []string{"tag"}, inventory[0].Tags)
+}
@@ -0,0 +1,64 @@
+package memoizer
+
+import "sort"
+
+// SnapshotEntry is a single key/value pair captured by a Snapshot.
+type SnapshotEntry[T any] struct {
+	Key   string
+	Value T
+}
+
+// Snapshot is an immutable, point-in-time view of the entries held by a Memoizer.
+// Entries are ordered by key, so paging through a Snapshot yields every entry
+// exactly once in a stable order, even while the underlying cache keeps changing.
+//
+// A Snapshot holds its own copy of every key plus a reference to every value that
+// was cached when it was taken. Values are not deep-copied, but holding a Snapshot
+// keeps them reachable, so a large Snapshot retains memory for entries that may have
+// since been expired or replaced in the cache. Drop it once pagination is finished.
+type Snapshot[T any] struct {
+	entries []SnapshotEntry[T]
+}
+
+// Snapshot captures all unexpired entries currently held by the Memoizer. Like Peek, it skips
+// expired entries retained only as a fallback for failed recomputations. It requires a store
+// that implements InspectableStore, and is empty otherwise.
+func (m *Memoizer[T]) Snapshot() *Snapshot[T] {
+	items := m.entries()
+	entries := make([]SnapshotEntry[T], 0, len(items))
+	for key, item := range items {
+		if !m.servable(key) {
+			continue
+		}
+		if value, ok := m.typed(item.Value); ok {
+			entries = append(entries, SnapshotEntry[T]{Key: key, Value: value})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return &Snapshot[T]{entries: entries}
+}
+
+// Len returns the number of entries captured by the Snapshot.
+func (s *Snapshot[T]) Len() int {
+	return len(s.entries)
+}
+
+// Page returns up to limit entries starting at offset. Out-of-range offsets yield an
+// empty page. The returned slice is a copy and may be modified freely by the caller.
+func (s *Snapshot[T]) Page(offset, limit int) []SnapshotEntry[T] {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || offset >= len(s.entries) {
+		return []SnapshotEntry[T]{}
+	}
+	end := offset + limit
+	if end > len(s.entries) || end < offset {
+		end = len(s.entries)
+	}
+	page := make([]SnapshotEntry[T], end-offset)
+	copy(page, s.entries[offset:end])
+	return page
+}
@@ -0,0 +1,45 @@
+package memoizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	for i := 0; i < 25; i++ {
+		i := i
+		_, err := memoizer.Memoize(fmt.Sprintf("key%02d", i), func() (int, error) {
+			return i, nil
+		})
+		require.NoError(t, err)
+	}
+
+	snapshot := memoizer.Snapshot()
+	require.Equal(t, 25, snapshot.Len())
+
+	// Mutating the cache after taking the snapshot must not affect pagination.
+	_, err := memoizer.Memoize("key99", func() (int, error) {
+		return 99, nil
+	})
+	require.NoError(t, err)
+
+	var seen []string
+	for offset := 0; offset < snapshot.Len(); offset += 10 {
+		for _, entry := range snapshot.Page(offset, 10) {
+			seen = append(seen, entry.Key)
+			assert.Equal(t, fmt.Sprintf("key%02d", entry.Value), entry.Key)
+		}
+	}
+	require.Len(t, seen, 25)
+	for i, key := range seen {
+		assert.Equal(t, fmt.Sprintf("key%02d", i), key, "entries should be visited once, in key order")
+	}
+
+	assert.Empty(t, snapshot.Page(25, 10))
+	assert.Empty(t, snapshot.Page(0, 0))
+	assert.Len(t, snapshot.Page(20, 10), 5)
+}
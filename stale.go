@@ -0,0 +1,21 @@
+package memoizer
+
+import "fmt"
+
+// StaleError is returned together with an expired cached value when recomputing it failed and
+// the call was made with a ServeStaleOnErrorOption that has Report set.
+type StaleError struct {
+	// Err is the error returned by the recomputation.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("serving stale value: %v", e.Err)
+}
+
+// Unwrap returns the recomputation error, so that errors.Is and errors.As can look through
+// a StaleError.
+func (e *StaleError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,147 @@
+package memoizer
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerWithStaleWhileRevalidate(t *testing.T) {
+	memoizer := NewMemoizer[int32]()
+	var calls int32
+	release := make(chan struct{}, 10)
+	fn := func() (int32, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if n > 1 {
+			<-release
+		}
+		return n, nil
+	}
+	options := []Option{
+		WithExpiration(func(interface{}) time.Duration {
+			return 50 * time.Millisecond
+		}),
+		WithStaleWhileRevalidate(200 * time.Millisecond),
+	}
+
+	result, err := memoizer.Memoize("key", fn, options...)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), result)
+
+	time.Sleep(70 * time.Millisecond)
+
+	// Past its TTL, the stale value is returned immediately while a refresh runs.
+	for i := 0; i < 3; i++ {
+		result, err = memoizer.Memoize("key", fn, options...)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), result)
+	}
+	release <- struct{}{}
+
+	assert.Eventually(t, func() bool {
+		result, _ := memoizer.Memoize("key", fn, options...)
+		return result == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "concurrent stale hits should share one refresh")
+}
+
+func TestMemoizerWithStaleWhileRevalidateHardExpiry(t *testing.T) {
+	memoizer := NewMemoizer[int32]()
+	var calls int32
+	fn := func() (int32, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}
+	options := []Option{
+		WithExpiration(func(interface{}) time.Duration {
+			return 20 * time.Millisecond
+		}),
+		WithStaleWhileRevalidate(20 * time.Millisecond),
+	}
+
+	_, err := memoizer.Memoize("key", fn, options...)
+	require.NoError(t, err)
+
+	// After the stale window, the entry is a regular miss.
+	time.Sleep(60 * time.Millisecond)
+	result, err := memoizer.Memoize("key", fn, options...)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), result)
+}
+
+func TestMemoizerWithServeStaleOnError(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	expiration := WithExpiration(func(interface{}) time.Duration {
+		return 20 * time.Millisecond
+	})
+	upstreamErr := errors.New("upstream unavailable")
+
+	result, err := memoizer.Memoize("key", func() (int, error) { return 1, nil }, expiration, WithServeStaleOnError(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	time.Sleep(40 * time.Millisecond)
+
+	// The expired value stands in for a failed recomputation.
+	var calls int
+	failing := func() (int, error) {
+		calls++
+		return 0, upstreamErr
+	}
+	result, err = memoizer.Memoize("key", failing, expiration, WithServeStaleOnError(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+	assert.Equal(t, 1, calls, "an expired entry should be recomputed")
+
+	// Callers that ask for it learn that the value is stale.
+	result, err = memoizer.Memoize("key", failing, expiration, &ServeStaleOnErrorOption{RetainFor: time.Second, Report: true})
+	assert.Equal(t, 1, result)
+	var staleErr *StaleError
+	require.ErrorAs(t, err, &staleErr)
+	assert.ErrorIs(t, err, upstreamErr)
+
+	// A successful recomputation replaces the expired value.
+	result, err = memoizer.Memoize("key", func() (int, error) { return 2, nil }, expiration, WithServeStaleOnError(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 2, result)
+}
+
+func TestMemoizerWithServeStaleOnErrorWithoutRetainedValue(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	upstreamErr := errors.New("upstream unavailable")
+
+	_, err := memoizer.Memoize("key", func() (int, error) { return 0, upstreamErr }, WithServeStaleOnError(time.Second))
+	assert.ErrorIs(t, err, upstreamErr)
+}
+
+func TestMemoizerWithSoftHardTTL(t *testing.T) {
+	memoizer := NewMemoizer[int32]()
+	var calls int32
+	fn := func() (int32, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}
+	ttl := WithSoftHardTTL(20*time.Millisecond, 60*time.Millisecond)
+
+	result, err := memoizer.Memoize("key", fn, ttl)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), result)
+
+	// Past the soft TTL, the cached value is served while it is refreshed in the background.
+	time.Sleep(30 * time.Millisecond)
+	result, err = memoizer.Memoize("key", fn, ttl)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), result)
+	assert.Eventually(t, func() bool {
+		result, _ := memoizer.Peek("key")
+		return result == 2
+	}, time.Second, 5*time.Millisecond)
+
+	// Past the hard TTL, the entry is a full miss.
+	time.Sleep(70 * time.Millisecond)
+	result, err = memoizer.Memoize("key", fn, ttl)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), result)
+}
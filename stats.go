@@ -0,0 +1,67 @@
+package memoizer
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Stats reports how effective a Memoizer's cache is, as returned by Memoizer.Stats.
+type Stats struct {
+	// Hits is the number of lookups served from the cache.
+	Hits uint64
+	// Misses is the number of lookups that were not served from the cache.
+	Misses uint64
+	// Evictions is the number of entries the store removed on its own, through expiry or to
+	// make room for other entries. Entries removed with Delete and its variants are not
+	// counted. It stays zero if the store is not an EvictionNotifier.
+	Evictions uint64
+	// Entries is the number of entries currently cached. It is taken from the store if it is
+	// an InspectableStore, and otherwise counts the entries stored by this Memoizer. Expired
+	// entries retained only as a fallback for failed recomputations are not counted.
+	Entries int
+	// InFlight is the number of computations currently running.
+	InFlight int64
+}
+
+// HitRate returns the fraction of lookups that were hits, or zero if there were no lookups.
+func (s Stats) HitRate() float64 {
+	return WindowCounts{Hits: s.Hits, Misses: s.Misses}.HitRate()
+}
+
+// statsCounters holds the counters reported by Stats.
+type statsCounters struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	inFlight  atomic.Int64
+	// deleting holds the keys being removed by Delete, whose eviction notifications are
+	// therefore not counted as evictions.
+	deleting sync.Map
+}
+
+// Stats returns the Memoizer's hit, miss and eviction counts since construction, along with
+// its current number of entries and in-flight computations, for monitoring.
+func (m *Memoizer[T]) Stats() Stats {
+	entries := 0
+	if store, ok := m.store.(InspectableStore); ok {
+		store.Range(func(key string, _ StoreEntry) bool {
+			if m.servable(key) {
+				entries++
+			}
+			return true
+		})
+	} else {
+		for _, key := range m.access.keys() {
+			if m.servable(key) {
+				entries++
+			}
+		}
+	}
+	return Stats{
+		Hits:      m.counters.hits.Load(),
+		Misses:    m.counters.misses.Load(),
+		Evictions: m.counters.evictions.Load(),
+		Entries:   entries,
+		InFlight:  m.counters.inFlight.Load(),
+	}
+}
@@ -0,0 +1,75 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerStats(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithMaxEntries(2))
+	fn := func() (int, error) { return 1, nil }
+
+	for _, key := range []string{"a", "a", "b", "a", "c"} {
+		_, err := memoizer.Memoize(key, fn)
+		require.NoError(t, err)
+	}
+	_, err := memoizer.Memoize("d", func() (int, error) { return 0, errors.New("failed") })
+	require.Error(t, err)
+	memoizer.Delete("a")
+	require.True(t, memoizer.Rename("c", "e"))
+
+	stats := memoizer.Stats()
+	assert.Equal(t, Stats{Hits: 2, Misses: 4, Evictions: 1, Entries: 1}, stats)
+	assert.InDelta(t, 2.0/6, stats.HitRate(), 0.001)
+	assert.Zero(t, Stats{}.HitRate())
+}
+
+func TestMemoizerStatsInFlight(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = memoizer.Memoize("key", func() (int, error) {
+			<-release
+			return 1, nil
+		})
+	}()
+
+	assert.Eventually(t, func() bool {
+		return memoizer.Stats().InFlight == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	<-done
+	assert.Zero(t, memoizer.Stats().InFlight)
+}
+
+func TestMemoizerWithComputeObserver(t *testing.T) {
+	type observation struct {
+		key    string
+		failed bool
+	}
+	var observed []observation
+	memoizer := NewMemoizerWithOptions[int](WithComputeObserver(func(key string, duration time.Duration, err error) {
+		assert.Positive(t, duration)
+		observed = append(observed, observation{key, err != nil})
+	}))
+
+	for i := 0; i < 2; i++ {
+		_, _ = memoizer.Memoize("ok", func() (int, error) {
+			time.Sleep(time.Millisecond)
+			return 1, nil
+		})
+	}
+	_, _ = memoizer.Memoize("failed", func() (int, error) {
+		time.Sleep(time.Millisecond)
+		return 0, errors.New("failed")
+	})
+
+	assert.Equal(t, []observation{{"ok", false}, {"failed", true}}, observed)
+	assert.True(t, memoizer.Config().ComputeObserver)
+}
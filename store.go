@@ -0,0 +1,131 @@
+package memoizer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// Store is the storage backend of a Memoizer. The default is an in-memory GoCacheStore;
+// other implementations, such as Redis or an embedded key-value store, can be plugged in
+// with WithStore. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored under key, unless it is missing or has expired.
+	Get(key string) (interface{}, bool)
+	// Set stores value under key. The entry expires after ttl, or never if ttl is negative,
+	// as with NoExpiration.
+	Set(key string, value interface{}, ttl time.Duration)
+	// Delete removes the entry stored under key, if any.
+	Delete(key string)
+	// Flush removes all entries.
+	Flush()
+}
+
+// StoreEntry is a value held by a Store, along with its expiration time.
+type StoreEntry struct {
+	Value interface{}
+	// ExpiresAt is the time the entry expires, or the zero time if it never expires.
+	ExpiresAt time.Time
+}
+
+// InspectableStore is a Store that can report expiration times and enumerate its entries.
+// Features such as Snapshot, Inventory, Iterator and TTL-preserving Rename require it.
+type InspectableStore interface {
+	Store
+	// GetWithExpiration is like Get, but also returns the time the entry expires, or the
+	// zero time if it never expires.
+	GetWithExpiration(key string) (interface{}, time.Time, bool)
+	// Entries returns a copy of all unexpired entries.
+	Entries() map[string]StoreEntry
+	// Range calls fn with each unexpired entry, in no particular order, until fn returns
+	// false. fn must not call the store.
+	Range(fn func(key string, entry StoreEntry) bool)
+}
+
+// EvictionNotifier is a Store that reports entries it removes, through expiry cleanup or
+// Delete, to a callback registered by the Memoizer.
+type EvictionNotifier interface {
+	OnEvicted(fn func(key string, value interface{}))
+}
+
+// GoCacheStore is the default Store, backed by an in-memory go-cache instance.
+// It implements InspectableStore and EvictionNotifier.
+type GoCacheStore struct {
+	*cache.Cache
+	stopJanitor func()
+}
+
+// NewGoCacheStore creates and returns a new GoCacheStore. Expired entries are purged every
+// cleanupInterval, or only when overwritten if cleanupInterval is zero. Purging runs in a
+// goroutine until Close is called.
+func NewGoCacheStore(cleanupInterval time.Duration) *GoCacheStore {
+	// go-cache's own janitor cannot be stopped, so the store runs its own.
+	s := &GoCacheStore{Cache: cache.New(cache.NoExpiration, 0)}
+	if cleanupInterval > 0 {
+		stop := make(chan struct{})
+		var once sync.Once
+		s.stopJanitor = func() { once.Do(func() { close(stop) }) }
+		go func(c *cache.Cache) {
+			ticker := time.NewTicker(cleanupInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					c.DeleteExpired()
+				case <-stop:
+					return
+				}
+			}
+		}(s.Cache)
+	}
+	return s
+}
+
+// Close stops purging expired entries in the background. The store remains usable.
+func (s *GoCacheStore) Close() {
+	if s.stopJanitor != nil {
+		s.stopJanitor()
+	}
+}
+
+// Set stores value under key. Unlike go-cache, a ttl of zero does not stand for a default
+// expiration; the Memoizer always resolves the expiration before storing.
+func (s *GoCacheStore) Set(key string, value interface{}, ttl time.Duration) {
+	if ttl == 0 {
+		ttl = NoExpiration
+	}
+	s.Cache.Set(key, value, ttl)
+}
+
+// Entries returns a copy of all unexpired entries.
+func (s *GoCacheStore) Entries() map[string]StoreEntry {
+	entries := make(map[string]StoreEntry, s.Cache.ItemCount())
+	s.Range(func(key string, entry StoreEntry) bool {
+		entries[key] = entry
+		return true
+	})
+	return entries
+}
+
+// Range calls fn with each unexpired entry until fn returns false. go-cache only exposes its
+// items by copying them, so Range still copies the item map, though not the entries.
+func (s *GoCacheStore) Range(fn func(key string, entry StoreEntry) bool) {
+	for key, item := range s.Cache.Items() {
+		entry := StoreEntry{Value: item.Object}
+		if item.Expiration > 0 {
+			entry.ExpiresAt = time.Unix(0, item.Expiration)
+		}
+		if !fn(key, entry) {
+			return
+		}
+	}
+}
+
+// entries returns the entries of the Memoizer's store, or nil if the store cannot enumerate them.
+func (m *Memoizer[T]) entries() map[string]StoreEntry {
+	if store, ok := m.store.(InspectableStore); ok {
+		return store.Entries()
+	}
+	return nil
+}
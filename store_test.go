@@ -0,0 +1,92 @@
+package memoizer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapStore is a minimal Store that records the TTL of every write.
+type mapStore struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+	ttls   map[string]time.Duration
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{values: make(map[string]interface{}), ttls: make(map[string]time.Duration)}
+}
+
+func (s *mapStore) Get(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func (s *mapStore) Set(key string, value interface{}, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	s.ttls[key] = ttl
+}
+
+func (s *mapStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	delete(s.ttls, key)
+}
+
+func (s *mapStore) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]interface{})
+	s.ttls = make(map[string]time.Duration)
+}
+
+func TestMemoizerWithStore(t *testing.T) {
+	store := newMapStore()
+	memoizer := NewMemoizerWithOptions[int](WithStore(store))
+
+	result, err := memoizer.Memoize("key", func() (int, error) {
+		return 42, nil
+	}, WithExpiration(func(interface{}) time.Duration {
+		return time.Minute
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, 42, store.values["key"])
+	assert.Equal(t, time.Minute, store.ttls["key"])
+
+	result, err = memoizer.Memoize("key", func() (int, error) {
+		return 0, errors.New("this should not be called")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+
+	// The default expiration is resolved before reaching the store.
+	_, err = memoizer.Memoize("other", func() (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, NoExpiration, store.ttls["other"])
+
+	// Features that enumerate entries need an InspectableStore.
+	assert.Equal(t, 0, memoizer.Snapshot().Len())
+}
+
+func TestGoCacheStore(t *testing.T) {
+	store := NewGoCacheStore(0)
+	store.Set("forever", 1, 0)
+	store.Set("short", 2, time.Minute)
+
+	entries := store.Entries()
+	require.Len(t, entries, 2)
+	assert.True(t, entries["forever"].ExpiresAt.IsZero())
+	assert.WithinDuration(t, time.Now().Add(time.Minute), entries["short"].ExpiresAt, time.Second)
+}
@@ -0,0 +1,137 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrTagLimit is reported, wrapped, to the OnError hook when tags are dropped because of
+// WithMaxTagsPerEntry or WithMaxDistinctTags.
+var ErrTagLimit = errors.New("tag limit exceeded")
+
+// tagIndex maps the tags attached with WithTags to the keys carrying them, and back.
+type tagIndex struct {
+	mu          sync.Mutex
+	maxPerEntry int // of WithMaxTagsPerEntry, or zero if unbounded
+	maxDistinct int // of WithMaxDistinctTags, or zero if unbounded
+	byTag       map[string]map[string]struct{}
+	byKey       map[string][]string
+}
+
+// set replaces the tags of key, and returns the tags it dropped to stay within the limits. A
+// key without tags is dropped from the index.
+func (t *tagIndex) set(key string, tags []string) (dropped []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(tags) == 0 && len(t.byKey[key]) == 0 {
+		return nil
+	}
+	t.removeLocked(key)
+	if t.maxPerEntry > 0 && len(tags) > t.maxPerEntry {
+		tags, dropped = tags[:t.maxPerEntry], append([]string(nil), tags[t.maxPerEntry:]...)
+	}
+	if len(tags) == 0 {
+		return dropped
+	}
+	if t.byTag == nil {
+		t.byTag = make(map[string]map[string]struct{})
+		t.byKey = make(map[string][]string)
+	}
+	kept := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		keys, ok := t.byTag[tag]
+		if !ok {
+			if t.maxDistinct > 0 && len(t.byTag) >= t.maxDistinct {
+				dropped = append(dropped, tag)
+				continue
+			}
+			keys = make(map[string]struct{})
+			t.byTag[tag] = keys
+		}
+		keys[key] = struct{}{}
+		kept = append(kept, tag)
+	}
+	if len(kept) > 0 {
+		t.byKey[key] = kept
+	}
+	return dropped
+}
+
+// remove drops key from the index once it has left the cache.
+func (t *tagIndex) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(key)
+}
+
+// removeLocked is remove for callers that hold t.mu.
+func (t *tagIndex) removeLocked(key string) {
+	for _, tag := range t.byKey[key] {
+		delete(t.byTag[tag], key)
+		if len(t.byTag[tag]) == 0 {
+			delete(t.byTag, tag)
+		}
+	}
+	delete(t.byKey, key)
+}
+
+// keys returns the keys carrying tag.
+func (t *tagIndex) keys(tag string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	keys := make([]string, 0, len(t.byTag[tag]))
+	for key := range t.byTag[tag] {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// tags returns the tags of key, sorted.
+func (t *tagIndex) tags(key string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.byKey[key]) == 0 {
+		return nil
+	}
+	tags := append([]string(nil), t.byKey[key]...)
+	sort.Strings(tags)
+	return tags
+}
+
+// reset empties the index, once the cache has been flushed.
+func (t *tagIndex) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.byTag = nil
+	t.byKey = nil
+}
+
+// setTags replaces the tags of key, and reports the tags dropped to stay within the limits of
+// WithMaxTagsPerEntry and WithMaxDistinctTags to the OnError hook and the logger.
+func (m *Memoizer[T]) setTags(key string, tags []string) {
+	dropped := m.tags.set(key, tags)
+	if len(dropped) == 0 {
+		return
+	}
+	err := fmt.Errorf("%w: dropped tags %q", ErrTagLimit, dropped)
+	if m.hooks.OnError != nil {
+		m.hooks.OnError(key, err, 0)
+	}
+	if m.logger != nil {
+		m.logger.Warn("memoizer dropped tags", "key", key, "tags", dropped)
+	}
+}
+
+// InvalidateTag is like Delete for every key whose entry was stored with tag through WithTags.
+func (m *Memoizer[T]) InvalidateTag(tag string) {
+	m.mu.Lock()
+	keys := m.tags.keys(tag)
+	for _, key := range keys {
+		m.delete(key)
+	}
+	m.mu.Unlock()
+
+	m.publishDeleted(keys)
+}
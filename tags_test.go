@@ -0,0 +1,94 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoizerInvalidateTag(t *testing.T) {
+	memoizer := NewMemoizer[string]()
+	store := func(key string, tags ...string) {
+		_, err := memoizer.Memoize(key, func() (string, error) {
+			return key, nil
+		}, WithTags(tags...))
+		require.NoError(t, err)
+	}
+	store("profile", "user:123")
+	store("members", "user:123", "org:7")
+	store("billing", "org:7")
+	store("other", "user:456")
+
+	memoizer.InvalidateTag("user:123")
+
+	entries := memoizer.store.(InspectableStore).Entries()
+	assert.NotContains(t, entries, "profile")
+	assert.NotContains(t, entries, "members")
+	assert.Contains(t, entries, "billing")
+	assert.Contains(t, entries, "other")
+
+	// The removed entry no longer carries its other tags.
+	assert.ElementsMatch(t, []string{"billing"}, memoizer.tags.keys("org:7"))
+}
+
+func TestMemoizerTagsReplacedOnStore(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	memoizer.SetAll(map[string]int{"key": 1}, WithTags("old"))
+	memoizer.SetAll(map[string]int{"key": 2}, WithTags("new"))
+
+	memoizer.InvalidateTag("old")
+	assert.Contains(t, memoizer.store.(InspectableStore).Entries(), "key")
+
+	inventory := memoizer.Inventory()
+	require.Len(t, inventory, 1)
+	assert.Equal(t, []string{"new"}, inventory[0].Tags)
+
+	memoizer.InvalidateTag("new")
+	assert.Empty(t, memoizer.Inventory())
+}
+
+func TestMemoizerTagsFollowRename(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	memoizer.SetAll(map[string]int{"old": 1}, WithTags("tag"))
+
+	require.True(t, memoizer.Rename("old", "new"))
+	memoizer.InvalidateTag("tag")
+
+	assert.Empty(t, memoizer.Inventory())
+}
+
+func TestMemoizerTagLimits(t *testing.T) {
+	var reported []error
+	memoizer := New[int](WithMaxTagsPerEntry(2), WithMaxDistinctTags(3), WithHooks(Hooks[int]{
+		OnError: func(key string, err error, latency time.Duration) {
+			reported = append(reported, err)
+		},
+	}))
+
+	// Tags beyond the per-entry limit are dropped; the entry is still cached.
+	memoizer.SetAll(map[string]int{"a": 1}, WithTags("t1", "t2", "t3"))
+	_, ok := memoizer.Peek("a")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"t1", "t2"}, memoizer.tags.tags("a"))
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], ErrTagLimit)
+
+	// New tags beyond the distinct limit are dropped, while tags in use are still attached.
+	memoizer.SetAll(map[string]int{"b": 2}, WithTags("t1", "t4"))
+	memoizer.SetAll(map[string]int{"c": 3}, WithTags("t5", "t2"))
+	assert.Equal(t, []string{"t2"}, memoizer.tags.tags("c"))
+	require.Len(t, reported, 2)
+	assert.Len(t, memoizer.tags.byTag, 3, "the index should stay within the limit")
+
+	// A tag frees its slot once no entry carries it.
+	memoizer.InvalidateTag("t4")
+	memoizer.SetAll(map[string]int{"d": 4}, WithTags("t5"))
+	assert.Equal(t, []string{"t5"}, memoizer.tags.tags("d"))
+	assert.Len(t, reported, 2)
+
+	config := memoizer.Config()
+	assert.Equal(t, 2, config.MaxTagsPerEntry)
+	assert.Equal(t, 3, config.MaxDistinctTags)
+}
@@ -0,0 +1,209 @@
+package memoizer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TieredStore is a Store that layers a fast local store, such as an in-process GoCacheStore,
+// over a slower shared one, such as Redis. Reads check the local tier first and fall back to
+// the shared tier, copying hits back into the local tier; writes and deletions go to both.
+type TieredStore struct {
+	local  Store
+	remote Store
+	// localTTL caps how long entries live in the local tier, or is zero if they live as long
+	// as in the remote tier.
+	localTTL time.Duration
+	// revalidate is how often local entries are checked against the remote tier, or zero if
+	// they are not; version orders the values of a key, if not nil.
+	revalidate time.Duration
+	version    func(value interface{}) int64
+
+	mu sync.Mutex
+	// checked holds when the local entry of each key was last copied from or checked against
+	// the remote tier. A key without a time is due for a check, so times older than revalidate
+	// are dropped once the map has doubled in size since they last were.
+	checked map[string]time.Time
+	sweepAt int
+}
+
+// minCheckedSweep is the number of keys with a check time below which a TieredStore does not
+// bother dropping old ones.
+const minCheckedSweep = 64
+
+// LocalRevalidateIntervalOption is a struct that implements the Option interface.
+// It holds how often a TieredStore checks its local entries against its remote tier.
+type LocalRevalidateIntervalOption struct {
+	Interval time.Duration
+	Version  func(value interface{}) int64
+}
+
+// WithLocalRevalidateInterval returns an Option for NewTieredStore that checks a local entry
+// against the remote tier when it is read more than interval after it was last copied from or
+// checked against the remote tier, so that values replaced in the remote tier by other
+// processes are picked up without waiting for the local entry to expire.
+//
+// The remote tier is authoritative: if it no longer holds the key, the local entry is dropped.
+// Otherwise, the remote value replaces the local one if version is nil, or if version reports a
+// higher version for it than for the local value, such as a revision number or update time kept
+// in the value; with a lower or equal version, the local value is kept. Other calls ignore this
+// option.
+var WithLocalRevalidateInterval = func(interval time.Duration, version func(value interface{}) int64) Option {
+	return &LocalRevalidateIntervalOption{Interval: interval, Version: version}
+}
+
+// NewTieredStore creates and returns a new TieredStore over the local and remote stores.
+// Entries live in the local tier for at most localTTL, which bounds how long one process can
+// serve a value after another has replaced it in the remote tier; a non-positive localTTL
+// keeps local entries as long as remote ones. That requires the remote tier to report how long
+// its entries live, as an InspectableStore does: otherwise values copied from it would never
+// expire locally, so NewTieredStore panics if localTTL is not positive. The only option is
+// WithLocalRevalidateInterval.
+func NewTieredStore(local, remote Store, localTTL time.Duration, options ...Option) *TieredStore {
+	if _, inspectable := remote.(InspectableStore); !inspectable && localTTL <= 0 {
+		panic(fmt.Sprintf("memoizer: NewTieredStore needs a positive local TTL over a %T remote tier, which does not report the lifetime of its entries", remote))
+	}
+	s := &TieredStore{local: local, remote: remote, localTTL: localTTL}
+	for _, option := range options {
+		if opt, ok := option.(*LocalRevalidateIntervalOption); ok && opt.Interval > 0 {
+			s.revalidate, s.version = opt.Interval, opt.Version
+		}
+	}
+	return s
+}
+
+// Local returns the local tier.
+func (s *TieredStore) Local() Store {
+	return s.local
+}
+
+// Remote returns the remote tier.
+func (s *TieredStore) Remote() Store {
+	return s.remote
+}
+
+// Get returns the value stored under key in the local tier or, failing that, in the remote
+// tier. A remote hit is copied into the local tier, for at most its remaining lifetime if the
+// remote tier is an InspectableStore. With WithLocalRevalidateInterval, a local hit that is due
+// for a check is checked against the remote tier first.
+func (s *TieredStore) Get(key string) (interface{}, bool) {
+	value, ok := s.local.Get(key)
+	if ok && !s.due(key) {
+		return value, true
+	}
+	return s.promote(key, value, ok)
+}
+
+// promote reads key from the remote tier and copies its value into the local tier, unless the
+// local tier holds value, if local is true, with a version that is not lower.
+func (s *TieredStore) promote(key string, value interface{}, local bool) (interface{}, bool) {
+	var (
+		remoteValue interface{}
+		expiresAt   time.Time
+		ok          bool
+	)
+	if remote, inspectable := s.remote.(InspectableStore); inspectable {
+		remoteValue, expiresAt, ok = remote.GetWithExpiration(key)
+	} else {
+		remoteValue, ok = s.remote.Get(key)
+	}
+	if !ok {
+		if local {
+			s.local.Delete(key)
+			s.forget(key)
+		}
+		return nil, false
+	}
+	s.check(key)
+	if local && s.version != nil && s.version(remoteValue) <= s.version(value) {
+		return value, true
+	}
+	ttl := NoExpiration
+	if !expiresAt.IsZero() {
+		if ttl = time.Until(expiresAt); ttl <= 0 {
+			return remoteValue, true
+		}
+	}
+	s.local.Set(key, remoteValue, s.capLocal(ttl))
+	return remoteValue, true
+}
+
+// Set stores value under key in both tiers, capping its lifetime in the local tier.
+func (s *TieredStore) Set(key string, value interface{}, ttl time.Duration) {
+	s.remote.Set(key, value, ttl)
+	s.local.Set(key, value, s.capLocal(ttl))
+	s.check(key)
+}
+
+// Delete removes the entry stored under key from both tiers.
+func (s *TieredStore) Delete(key string) {
+	s.remote.Delete(key)
+	s.local.Delete(key)
+	s.forget(key)
+}
+
+// Flush removes all entries from both tiers.
+func (s *TieredStore) Flush() {
+	s.remote.Flush()
+	s.local.Flush()
+	s.mu.Lock()
+	s.checked = nil
+	s.mu.Unlock()
+}
+
+// capLocal returns the lifetime of an entry in the local tier, given its lifetime ttl in the
+// remote tier.
+func (s *TieredStore) capLocal(ttl time.Duration) time.Duration {
+	if s.localTTL > 0 && (ttl < 0 || ttl > s.localTTL) {
+		return s.localTTL
+	}
+	return ttl
+}
+
+// due reports whether the local entry of key is to be checked against the remote tier.
+func (s *TieredStore) due(key string) bool {
+	if s.revalidate <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkedAt, ok := s.checked[key]
+	return !ok || time.Since(checkedAt) >= s.revalidate
+}
+
+// check records that the local entry of key matches the remote tier as of now.
+func (s *TieredStore) check(key string) {
+	if s.revalidate <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.checked == nil {
+		s.checked = make(map[string]time.Time)
+	}
+	if _, ok := s.checked[key]; !ok && len(s.checked) >= s.sweepAt {
+		for key, checkedAt := range s.checked {
+			if now.Sub(checkedAt) >= s.revalidate {
+				delete(s.checked, key)
+			}
+		}
+		s.sweepAt = 2 * len(s.checked)
+		if s.sweepAt < minCheckedSweep {
+			s.sweepAt = minCheckedSweep
+		}
+	}
+	s.checked[key] = now
+}
+
+// forget drops the time the local entry of key was last checked.
+func (s *TieredStore) forget(key string) {
+	if s.revalidate <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checked, key)
+}
@@ -0,0 +1,141 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTieredStore(t *testing.T) {
+	local := NewGoCacheStore(0)
+	remote := NewGoCacheStore(0)
+	store := NewTieredStore(local, remote, time.Minute)
+
+	store.Set("key", 1, time.Hour)
+	_, localExpiresAt, ok := local.GetWithExpiration("key")
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), localExpiresAt, time.Second, "the local TTL should be capped")
+	_, remoteExpiresAt, ok := remote.GetWithExpiration("key")
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), remoteExpiresAt, time.Second)
+
+	// A remote hit is backfilled into the local tier.
+	local.Flush()
+	value, ok := store.Get("key")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+	_, ok = local.Get("key")
+	assert.True(t, ok)
+
+	// The local tier answers without consulting the remote one.
+	remote.Set("key", 2, NoExpiration)
+	value, _ = store.Get("key")
+	assert.Equal(t, 1, value)
+
+	store.Delete("key")
+	_, ok = store.Get("key")
+	assert.False(t, ok)
+}
+
+func TestTieredStoreBackfillKeepsRemainingLifetime(t *testing.T) {
+	local := NewGoCacheStore(0)
+	remote := NewGoCacheStore(0)
+	store := NewTieredStore(local, remote, time.Hour)
+
+	remote.Set("key", 1, time.Minute)
+	_, ok := store.Get("key")
+	require.True(t, ok)
+
+	_, expiresAt, ok := local.GetWithExpiration("key")
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+}
+
+func TestTieredStoreOverNonInspectableRemote(t *testing.T) {
+	local := NewGoCacheStore(0)
+	remote := newMapStore()
+
+	// The lifetime of remote entries is unknown, so local copies must expire on their own.
+	assert.Panics(t, func() { NewTieredStore(local, remote, 0) })
+
+	store := NewTieredStore(local, remote, time.Minute)
+	remote.Set("key", 1, NoExpiration)
+	_, ok := store.Get("key")
+	require.True(t, ok)
+
+	_, expiresAt, ok := local.GetWithExpiration("key")
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+}
+
+func TestTieredStoreWithLocalRevalidateInterval(t *testing.T) {
+	local := NewGoCacheStore(0)
+	remote := NewGoCacheStore(0)
+	store := NewTieredStore(local, remote, time.Hour, WithLocalRevalidateInterval(50*time.Millisecond, nil))
+
+	store.Set("key", 1, NoExpiration)
+	remote.Set("key", 2, NoExpiration)
+	value, _ := store.Get("key")
+	assert.Equal(t, 1, value, "the local entry should be served until the interval passes")
+
+	time.Sleep(60 * time.Millisecond)
+	value, ok := store.Get("key")
+	require.True(t, ok)
+	assert.Equal(t, 2, value, "the local entry should pick up the newer remote value")
+	value, _ = local.Get("key")
+	assert.Equal(t, 2, value)
+
+	// A key removed from the remote tier is dropped locally.
+	remote.Delete("key")
+	time.Sleep(60 * time.Millisecond)
+	_, ok = store.Get("key")
+	assert.False(t, ok)
+	_, ok = local.Get("key")
+	assert.False(t, ok)
+}
+
+func TestTieredStoreWithLocalRevalidateIntervalVersions(t *testing.T) {
+	type versioned struct {
+		Revision int64
+		Value    string
+	}
+	local := NewGoCacheStore(0)
+	remote := NewGoCacheStore(0)
+	store := NewTieredStore(local, remote, time.Hour, WithLocalRevalidateInterval(50*time.Millisecond, func(value interface{}) int64 {
+		return value.(versioned).Revision
+	}))
+
+	store.Set("key", versioned{Revision: 2, Value: "new"}, NoExpiration)
+	// A lagging write of an older revision to the remote tier does not replace the local value.
+	remote.Set("key", versioned{Revision: 1, Value: "old"}, NoExpiration)
+	time.Sleep(60 * time.Millisecond)
+	value, _ := store.Get("key")
+	assert.Equal(t, "new", value.(versioned).Value)
+
+	remote.Set("key", versioned{Revision: 3, Value: "newer"}, NoExpiration)
+	time.Sleep(60 * time.Millisecond)
+	value, _ = store.Get("key")
+	assert.Equal(t, "newer", value.(versioned).Value)
+}
+
+func TestMemoizerWithTieredStore(t *testing.T) {
+	remote := newMapStore()
+	first := NewMemoizerWithOptions[int](WithStore(NewTieredStore(NewGoCacheStore(0), remote, time.Minute)))
+	second := NewMemoizerWithOptions[int](WithStore(NewTieredStore(NewGoCacheStore(0), remote, time.Minute)))
+
+	_, err := first.Memoize("key", func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+
+	result, err := second.Memoize("key", func() (int, error) {
+		t.Fatal("the value should come from the shared tier")
+		return 0, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+
+	first.Flush()
+	_, ok := second.Peek("key")
+	assert.True(t, ok, "the local tier of other processes is only flushed by their own calls")
+}
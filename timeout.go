@@ -0,0 +1,60 @@
+package memoizer
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrComputeTimeout is returned when a computation does not finish within the time allowed by
+// WithTimeout.
+var ErrComputeTimeout = errors.New("computation timed out")
+
+// withTimeout bounds the running time of compute, the computation of key with fn, to
+// opts.timeout. compute keeps running after it times out, since it cannot be stopped; with
+// opts.cacheLate, its outcome is cached once it finishes as if it had finished in time, unless
+// the cache was invalidated in the meantime.
+func (m *Memoizer[T]) withTimeout(key string, fn, compute func() (T, error), opts callOptions[T]) func() (T, error) {
+	return func() (T, error) {
+		type outcome struct {
+			res T
+			err error
+			p   *recoveredPanic
+		}
+		start, generation := time.Now(), m.generation.Load()
+		done := make(chan outcome, 1)
+		go func() {
+			res, err, p := callRecovering(compute)
+			done <- outcome{res: res, err: err, p: p}
+		}()
+
+		timer := time.NewTimer(opts.timeout)
+		defer timer.Stop()
+		select {
+		case o := <-done:
+			if o.p != nil {
+				panic(o.p)
+			}
+			return o.res, o.err
+		case <-timer.C:
+			if opts.cacheLate {
+				m.activity.join()
+				go func() {
+					defer m.activity.leave()
+					o := <-done
+					if o.p != nil {
+						return
+					}
+					// Holding m.mu orders the check with Delete and Flush, which invalidate
+					// under it: a result computed before an invalidation must not outlive it.
+					m.mu.Lock()
+					defer m.mu.Unlock()
+					if m.generation.Load() == generation {
+						m.keep(key, fn, o.res, o.err, start, time.Since(start), opts)
+					}
+				}()
+			}
+			var zero T
+			return zero, ErrComputeTimeout
+		}
+	}
+}
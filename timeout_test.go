@@ -0,0 +1,98 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutReturnsErrComputeTimeout(t *testing.T) {
+	m := NewMemoizer[int]()
+	release := make(chan struct{})
+	defer close(release)
+
+	start := time.Now()
+	_, err := m.Memoize("key", func() (int, error) {
+		<-release
+		return 1, nil
+	}, WithTimeout(10*time.Millisecond))
+	assert.ErrorIs(t, err, ErrComputeTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTimeoutDiscardsLateResultByDefault(t *testing.T) {
+	m := NewMemoizer[int]()
+	finished := make(chan struct{})
+
+	_, err := m.Memoize("key", func() (int, error) {
+		defer close(finished)
+		time.Sleep(30 * time.Millisecond)
+		return 1, nil
+	}, WithTimeout(5*time.Millisecond))
+	require.ErrorIs(t, err, ErrComputeTimeout)
+
+	<-finished
+	time.Sleep(10 * time.Millisecond)
+	_, ok := m.Peek("key")
+	assert.False(t, ok)
+}
+
+func TestTimeoutCachesLateResult(t *testing.T) {
+	m := NewMemoizer[int]()
+
+	_, err := m.Memoize("key", func() (int, error) {
+		time.Sleep(30 * time.Millisecond)
+		return 1, nil
+	}, &TimeoutOption{Timeout: 5 * time.Millisecond, CacheLate: true})
+	require.ErrorIs(t, err, ErrComputeTimeout)
+
+	assert.Eventually(t, func() bool {
+		value, ok := m.Peek("key")
+		return ok && value == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestTimeoutLateResultFollowsCachingRules(t *testing.T) {
+	m := NewMemoizer[int]()
+	late := func(value int) func() (int, error) {
+		return func() (int, error) {
+			time.Sleep(20 * time.Millisecond)
+			return value, nil
+		}
+	}
+	timeout := &TimeoutOption{Timeout: 5 * time.Millisecond, CacheLate: true}
+
+	// A late result rejected by the cache predicate is not cached.
+	_, err := m.Memoize("rejected", late(0), timeout, WithCachePredicate(func(value int) bool { return value != 0 }))
+	require.ErrorIs(t, err, ErrComputeTimeout)
+
+	// A late result computed before the key was deleted is not cached.
+	_, err = m.Memoize("deleted", late(1), timeout)
+	require.ErrorIs(t, err, ErrComputeTimeout)
+	m.Delete("deleted")
+
+	time.Sleep(40 * time.Millisecond)
+	_, ok := m.Peek("rejected")
+	assert.False(t, ok)
+	_, ok = m.Peek("deleted")
+	assert.False(t, ok, "a late result should not resurrect a deleted key")
+}
+
+func TestTimeoutPassesThroughFastResults(t *testing.T) {
+	m := NewMemoizer[int]()
+	failure := errors.New("failure")
+
+	value, err := m.Memoize("key", func() (int, error) { return 1, nil }, WithTimeout(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	_, err = m.Memoize("other", func() (int, error) { return 0, failure }, WithTimeout(time.Second))
+	assert.ErrorIs(t, err, failure)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = m.Memoize("panics", func() (int, error) { panic("boom") }, WithTimeout(time.Second))
+	})
+}
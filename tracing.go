@@ -0,0 +1,37 @@
+package memoizer
+
+import "context"
+
+// CallTracer instruments the lookups of a Memoizer, as installed with WithCallTracer. The
+// context passed to it is the one given to MemoizeContext, or context.Background() for calls
+// that take none.
+type CallTracer interface {
+	// Hit is called when a lookup of key is served from the cache.
+	Hit(ctx context.Context, key string)
+	// Miss is called when a caller starts waiting for the computation of key, and returns a
+	// function that is called once the caller has its outcome: the error of the computation,
+	// and whether the computation was shared with other callers.
+	Miss(ctx context.Context, key string) (end func(err error, shared bool))
+}
+
+// traceHit reports a cache hit to the CallTracer, if any.
+func (m *Memoizer[T]) traceHit(ctx context.Context, key string) {
+	if m.tracer != nil {
+		m.tracer.Hit(ctx, key)
+	}
+}
+
+// traceMiss reports a cache miss to the CallTracer, if any, and returns the function to call
+// with its outcome. A panic in the computation is reported as a *PanicError.
+func (m *Memoizer[T]) traceMiss(ctx context.Context, key string) func(err error, shared bool) {
+	if m.tracer == nil {
+		return func(error, bool) {}
+	}
+	end := m.tracer.Miss(ctx, key)
+	return func(err error, shared bool) {
+		if p, ok := err.(*recoveredPanic); ok {
+			err = &PanicError{Value: p.value, Stack: p.stack}
+		}
+		end(err, shared)
+	}
+}
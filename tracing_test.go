@@ -0,0 +1,55 @@
+package memoizer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTracer is a CallTracer that records the calls it receives.
+type recordingTracer struct {
+	mu     sync.Mutex
+	hits   []string
+	misses []string
+	errs   []error
+}
+
+func (r *recordingTracer) Hit(_ context.Context, key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits = append(r.hits, key)
+}
+
+func (r *recordingTracer) Miss(_ context.Context, key string) func(err error, shared bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.misses = append(r.misses, key)
+	return func(err error, _ bool) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.errs = append(r.errs, err)
+	}
+}
+
+func TestMemoizerWithCallTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	memoizer := NewMemoizerWithOptions[int](WithCallTracer(tracer))
+
+	_, _ = memoizer.Memoize("a", func() (int, error) { return 1, nil })
+	_, _ = memoizer.Memoize("a", func() (int, error) { return 1, nil })
+	<-memoizer.MemoizeDoChan("b", func() (int, error) { return 2, nil })
+	<-memoizer.MemoizeDoChan("b", func() (int, error) { return 2, nil })
+	assert.Panics(t, func() {
+		_, _ = memoizer.Memoize("c", func() (int, error) { panic("boom") })
+	})
+
+	assert.Equal(t, []string{"a", "b"}, tracer.hits)
+	assert.Equal(t, []string{"a", "b", "c"}, tracer.misses)
+	assert.Len(t, tracer.errs, 3)
+	assert.NoError(t, tracer.errs[0])
+	var panicErr *PanicError
+	assert.ErrorAs(t, tracer.errs[2], &panicErr)
+	assert.True(t, memoizer.Config().CallTracing)
+}
@@ -0,0 +1,91 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLBounds(t *testing.T) {
+	memoizer := NewMemoizerWithCacheExpiration[int](time.Hour)
+	callback := func(d time.Duration) Option {
+		return WithExpiration(func(interface{}) time.Duration {
+			return d
+		})
+	}
+	resolve := func(options ...Option) time.Duration {
+		return memoizer.expiration(0, resolveOptions[int](options))
+	}
+
+	// A callback returning zero or a negative duration is clamped to the floor.
+	assert.Equal(t, time.Second, resolve(callback(0), WithMinTTL(time.Second)))
+	assert.Equal(t, time.Second, resolve(callback(-5*time.Second), WithMinTTL(time.Second)))
+	// A huge value is clamped to the ceiling.
+	assert.Equal(t, time.Minute, resolve(callback(1000*time.Hour), WithMaxTTL(time.Minute)))
+	// Values within bounds are kept.
+	assert.Equal(t, 10*time.Second, resolve(callback(10*time.Second), WithMinTTL(time.Second), WithMaxTTL(time.Minute)))
+	// The default expiration is subject to the bounds too.
+	assert.Equal(t, time.Hour, resolve(WithMinTTL(time.Second)))
+	assert.Equal(t, time.Minute, resolve(WithMaxTTL(time.Minute)))
+
+	// NoExpiration is exempt from the floor but capped by the ceiling.
+	assert.Equal(t, NoExpiration, resolve(callback(NoExpiration), WithMinTTL(time.Second)))
+	assert.Equal(t, time.Minute, resolve(callback(NoExpiration), WithMaxTTL(time.Minute)))
+	assert.Equal(t, time.Minute, NewMemoizer[int]().expiration(0, resolveOptions[int]([]Option{WithMaxTTL(time.Minute)})))
+}
+
+func TestMemoizerWithMinTTL(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+
+	_, err := memoizer.Memoize("key", func() (int, error) {
+		return 42, nil
+	}, WithExpiration(func(interface{}) time.Duration {
+		return 0
+	}), WithMinTTL(time.Minute))
+	assert.NoError(t, err)
+
+	_, expiresAt, ok := memoizer.store.(InspectableStore).GetWithExpiration("key")
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+}
+
+func TestMemoizerWithExpirationFor(t *testing.T) {
+	type token struct {
+		value     string
+		expiresIn time.Duration
+	}
+	memoizer := NewMemoizer[token]()
+
+	_, err := memoizer.Memoize("key", func() (token, error) {
+		return token{value: "secret", expiresIn: time.Minute}, nil
+	}, WithExpirationFor(func(result token) time.Duration {
+		return result.expiresIn
+	}))
+	assert.NoError(t, err)
+
+	_, expiresAt, ok := memoizer.store.(InspectableStore).GetWithExpiration("key")
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+	// A callback for another type is ignored.
+	assert.Equal(t, NoExpiration, memoizer.expiration(token{}, resolveOptions[token]([]Option{WithExpirationFor(func(int) time.Duration {
+		return time.Minute
+	})})))
+}
+
+func TestWithTTL(t *testing.T) {
+	memoizer := NewMemoizerWithCacheExpiration[int](time.Hour)
+	resolve := func(options ...Option) time.Duration {
+		return memoizer.expiration(0, resolveOptions[int](options))
+	}
+
+	assert.Equal(t, time.Minute, resolve(WithTTL(time.Minute)))
+	assert.Equal(t, NoExpiration, resolve(WithTTL(NoExpiration)))
+	// Zero stands for the default expiration, even with a floor.
+	assert.Equal(t, time.Hour, resolve(WithTTL(0)))
+	assert.Equal(t, time.Hour, resolve(WithTTL(0), WithMinTTL(time.Second)))
+	// WithTTL replaces an earlier expiration callback, and is subject to the bounds.
+	assert.Equal(t, time.Minute, resolve(WithExpiration(func(interface{}) time.Duration { return time.Second }), WithTTL(time.Minute)))
+	assert.Equal(t, 10*time.Second, resolve(WithTTL(time.Minute), WithMaxTTL(10*time.Second)))
+}
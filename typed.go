@@ -0,0 +1,87 @@
+package memoizer
+
+import "time"
+
+// TypedOptions builds the Options whose callbacks receive results as T. Typed option
+// constructors such as WithExpirationFor infer T from their callback, and a callback of the
+// wrong type makes the option silently ignored; the methods of a TypedOptions bound to the
+// Memoizer through Options fix T instead, so the same mistake fails to compile.
+//
+// The Options it builds are ordinary Options, and mix freely with the untyped ones.
+//
+// Example usage:
+//
+//	users := memoizer.New[User]()
+//	opt := users.Options()
+//	users.Memoize("user:42", fetchUser, opt.Expiration(func(u User) time.Duration {
+//	    return time.Until(u.SessionExpiresAt)
+//	}), opt.CachePredicate(func(u User) bool {
+//	    return u.ID != 0
+//	}))
+type TypedOptions[T any] struct{}
+
+// OptionsFor returns the TypedOptions of a Memoizer[T], for building options before the
+// Memoizer exists, such as its constructor options.
+func OptionsFor[T any]() TypedOptions[T] {
+	return TypedOptions[T]{}
+}
+
+// Options returns the TypedOptions of the Memoizer.
+func (m *Memoizer[T]) Options() TypedOptions[T] {
+	return TypedOptions[T]{}
+}
+
+// Expiration is WithExpirationFor.
+func (TypedOptions[T]) Expiration(callback func(result T) time.Duration) Option {
+	return WithExpirationFor(callback)
+}
+
+// CachePredicate is WithCachePredicate.
+func (TypedOptions[T]) CachePredicate(cacheable func(result T) bool) Option {
+	return WithCachePredicate(cacheable)
+}
+
+// Fallback is WithFallback.
+func (TypedOptions[T]) Fallback(fallback func(key string, err error) (T, error)) Option {
+	return WithFallback(fallback)
+}
+
+// PanicFallback is WithPanicFallback.
+func (TypedOptions[T]) PanicFallback(fallback func() (T, error)) Option {
+	return WithPanicFallback(fallback)
+}
+
+// AfterCompute is WithAfterCompute.
+func (TypedOptions[T]) AfterCompute(hook func(key string, value T, set func(k string, v T, opts ...Option))) Option {
+	return WithAfterCompute(hook)
+}
+
+// ResultRetry is WithResultRetry.
+func (TypedOptions[T]) ResultRetry(maxAttempts int, valid func(result T) bool) Option {
+	return WithResultRetry(maxAttempts, valid)
+}
+
+// AuditLog is WithAuditLogFor.
+func (TypedOptions[T]) AuditLog(log func(entry AuditEntry), summarize func(result T) string) Option {
+	return WithAuditLogFor(log, summarize)
+}
+
+// Hooks is WithHooks, a constructor Option.
+func (TypedOptions[T]) Hooks(hooks Hooks[T]) Option {
+	return WithHooks(hooks)
+}
+
+// Clone is WithClone, a constructor Option.
+func (TypedOptions[T]) Clone(clone func(value T) T) Option {
+	return WithClone(clone)
+}
+
+// FlexibleDecode is WithFlexibleDecode, a constructor Option.
+func (TypedOptions[T]) FlexibleDecode(decode func(raw interface{}) (T, bool)) Option {
+	return WithFlexibleDecode(decode)
+}
+
+// MaxCost is WithMaxCostFor, a constructor Option.
+func (TypedOptions[T]) MaxCost(maxCost int64, cost func(value T) int64) Option {
+	return WithMaxCostFor(maxCost, cost)
+}
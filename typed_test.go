@@ -0,0 +1,64 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedOptions(t *testing.T) {
+	memoizer := NewMemoizerWithCacheExpiration[string](time.Hour)
+	opt := memoizer.Options()
+
+	opts := memoizer.resolve([]Option{opt.Expiration(func(result string) time.Duration {
+		return time.Duration(len(result)) * time.Second
+	})})
+	assert.Equal(t, 3*time.Second, memoizer.expiration("abc", opts))
+
+	_, err := memoizer.Memoize("empty", func() (string, error) { return "", nil }, opt.CachePredicate(func(result string) bool {
+		return result != ""
+	}))
+	require.NoError(t, err)
+	_, ok := memoizer.lookup("empty")
+	assert.False(t, ok)
+
+	value, err := memoizer.Memoize("failing", func() (string, error) { return "", errors.New("failed") }, opt.Fallback(func(key string, err error) (string, error) {
+		return "fallback for " + key, nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "fallback for failing", value)
+
+	assert.NoError(t, memoizer.Validate(opt.Expiration(func(string) time.Duration { return time.Minute }), opt.ResultRetry(2, func(string) bool { return true })))
+}
+
+func TestWithMaxCostFor(t *testing.T) {
+	memoizer := New[string](OptionsFor[string]().MaxCost(10, func(value string) int64 {
+		return int64(len(value))
+	}))
+	for _, key := range []string{"aaaa", "bbbb", "cccc"} {
+		key := key
+		_, err := memoizer.Memoize(key, func() (string, error) { return key, nil })
+		require.NoError(t, err)
+	}
+
+	assert.ElementsMatch(t, []string{"bbbb", "cccc"}, keysOf(memoizer.store.(InspectableStore).Entries()))
+}
+
+func TestWithAuditLogFor(t *testing.T) {
+	memoizer := NewMemoizer[[]int]()
+	var entries []AuditEntry
+	audit := WithAuditLogFor(func(entry AuditEntry) {
+		entries = append(entries, entry)
+	}, func(result []int) string {
+		return fmt.Sprintf("%d items", len(result))
+	})
+
+	_, err := memoizer.Memoize("key", func() ([]int, error) { return []int{1, 2, 3}, nil }, audit)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "3 items", entries[0].Summary)
+}
@@ -0,0 +1,44 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeMismatchIsRecomputed(t *testing.T) {
+	store := newMapStore()
+	strings := NewMemoizerWithOptions[string](WithStore(store))
+	ints := NewMemoizerWithOptions[int](WithStore(store))
+
+	_, err := strings.Memoize("shared", func() (string, error) { return "text", nil })
+	require.NoError(t, err)
+
+	_, ok := ints.Peek("shared")
+	assert.False(t, ok)
+	value, err := ints.Memoize("shared", func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	value, err = ints.Memoize("shared", func() (int, error) { return 0, errors.New("this should not be called") })
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
+
+func TestStrictTypesPanicsWithErrTypeMismatch(t *testing.T) {
+	store := newMapStore()
+	store.Set("shared", "text", NoExpiration)
+	m := NewMemoizerWithOptions[int](WithStore(store), WithStrictTypes())
+	assert.True(t, m.Config().StrictTypes)
+
+	defer func() {
+		err, ok := recover().(error)
+		require.True(t, ok)
+		assert.ErrorIs(t, err, ErrTypeMismatch)
+		assert.EqualError(t, err, "cache value type mismatch: cached value of type string")
+	}()
+	_, _ = m.Memoize("shared", func() (int, error) { return 42, nil })
+	t.Fatal("expected a panic")
+}
@@ -0,0 +1,277 @@
+package memoizer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ErrInvalidOption is returned, wrapped, for an option that is invalid on its own or that
+// conflicts with another option of the same list.
+var ErrInvalidOption = errors.New("invalid option")
+
+// Validate checks a list of options, such as the options of a call or of New, and returns an
+// error wrapping ErrInvalidOption for each problem it finds, joined with errors.Join. It
+// reports negative durations and counts, nil callbacks, typed options whose type parameter
+// does not match T, options of unknown types, and options that conflict with each other in
+// the same list, such as WithTTL together with WithExpiration, rather than letting the later
+// one silently win. Call options overriding the defaults set with WithDefaultCallOptions are
+// not conflicts.
+//
+// Calls do not validate their options unless the Memoizer was created with
+// WithOptionValidation.
+func (m *Memoizer[T]) Validate(options ...Option) error {
+	return validateOptions[T](options)
+}
+
+// validateOptions implements Validate.
+func validateOptions[T any](options []Option) error {
+	var errs []error
+	invalid := func(option Option, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: %T: %s", ErrInvalidOption, option, fmt.Sprintf(format, args...)))
+	}
+	negative := func(option Option, name string, d time.Duration) {
+		if d < 0 {
+			invalid(option, "negative %s %v", name, d)
+		}
+	}
+	// expiration and staleness are the options that last set each of these, to report conflicts.
+	var expiration, staleness Option
+	setExpiration := func(option Option) {
+		if expiration != nil {
+			invalid(option, "conflicts with %T", expiration)
+		}
+		expiration = option
+	}
+	setStaleness := func(option Option) {
+		if staleness != nil {
+			invalid(option, "conflicts with %T", staleness)
+		}
+		staleness = option
+	}
+	var minTTL, maxTTL time.Duration
+
+	for _, option := range options {
+		switch opt := option.(type) {
+		case *ExpirationOption:
+			setExpiration(opt)
+			if opt.Callback == nil {
+				invalid(opt, "nil callback")
+			}
+		case *ExpirationForOption[T]:
+			setExpiration(opt)
+			if opt.Callback == nil {
+				invalid(opt, "nil callback")
+			}
+		case *TTLOption:
+			setExpiration(opt)
+			if opt.TTL < 0 && opt.TTL != NoExpiration {
+				invalid(opt, "negative TTL %v", opt.TTL)
+			}
+		case *SoftHardTTLOption:
+			setExpiration(opt)
+			setStaleness(opt)
+			if opt.Soft <= 0 || opt.Hard < opt.Soft {
+				invalid(opt, "soft TTL %v must be positive and not exceed hard TTL %v", opt.Soft, opt.Hard)
+			}
+		case *StaleWhileRevalidateOption:
+			setStaleness(opt)
+			negative(opt, "stale period", opt.StaleFor)
+		case *TTLBoundsOption:
+			negative(opt, "minimum TTL", opt.Min)
+			negative(opt, "maximum TTL", opt.Max)
+			if opt.Min != 0 {
+				minTTL = opt.Min
+			}
+			if opt.Max != 0 {
+				maxTTL = opt.Max
+			}
+			if minTTL > 0 && maxTTL > 0 && minTTL > maxTTL {
+				invalid(opt, "minimum TTL %v exceeds maximum TTL %v", minTTL, maxTTL)
+			}
+		case *AdmissionThresholdOption:
+			if opt.Threshold < 0 {
+				invalid(opt, "negative threshold %d", opt.Threshold)
+			}
+		case *ContextKeyAugmenterOption:
+			if opt.Augment == nil {
+				invalid(opt, "nil callback")
+			}
+		case *PanicFallbackOption[T]:
+			if opt.Fallback == nil {
+				invalid(opt, "nil callback")
+			}
+		case *FallbackOption[T]:
+			if opt.Fallback == nil {
+				invalid(opt, "nil callback")
+			}
+		case *AuditLogOption:
+			if opt.Log == nil {
+				invalid(opt, "nil callback")
+			}
+		case *AfterComputeOption[T]:
+			if opt.Hook == nil {
+				invalid(opt, "nil callback")
+			}
+		case *ResultRetryOption[T]:
+			if opt.Valid == nil {
+				invalid(opt, "nil callback")
+			}
+			if opt.MaxAttempts < 0 {
+				invalid(opt, "negative attempts %d", opt.MaxAttempts)
+			}
+		case *CachePredicateOption[T]:
+			if opt.Cacheable == nil {
+				invalid(opt, "nil callback")
+			}
+		case *ServeStaleOnErrorOption:
+			negative(opt, "retention", opt.RetainFor)
+		case *ErrorCachingOption:
+			if opt.TTL == nil {
+				invalid(opt, "nil callback")
+			}
+		case *TimeoutOption:
+			negative(opt, "timeout", opt.Timeout)
+		case *RetryOption:
+			if opt.Attempts < 0 {
+				invalid(opt, "negative attempts %d", opt.Attempts)
+			}
+		case *AutoRefreshOption:
+			negative(opt, "interval", opt.Interval)
+		case *EarlyExpirationOption:
+			if opt.Beta < 0 {
+				invalid(opt, "negative beta %v", opt.Beta)
+			}
+		case *TTLJitterOption:
+			if opt.Fraction < 0 || opt.Fraction >= 1 {
+				invalid(opt, "fraction %v outside [0, 1)", opt.Fraction)
+			}
+		case *MaxWaitersOption:
+			if opt.MaxWaiters < 0 {
+				invalid(opt, "negative limit %d", opt.MaxWaiters)
+			}
+		case *MinRecomputeIntervalOption:
+			negative(opt, "interval", opt.Interval)
+		case *HedgingOption:
+			negative(opt, "delay", opt.Delay)
+		case *ComputeObserverOption:
+			if opt.Observe == nil {
+				invalid(opt, "nil callback")
+			}
+		case *CallTracerOption:
+			if opt.Tracer == nil {
+				invalid(opt, "nil tracer")
+			}
+		case *KeyFuncOption:
+			if opt.KeyFunc == nil {
+				invalid(opt, "nil callback")
+			}
+		case *BackendOption:
+			if opt.Store == nil {
+				invalid(opt, "nil store")
+			}
+		case *DetachedContextOption, *EagerBackgroundFillOption, *SlidingExpirationOption,
+			*PanicAsErrorOption, *ForceRefreshOption, *TagsOption:
+
+		// Constructor options.
+		case *StoreOption:
+			if opt.Store == nil {
+				invalid(opt, "nil store")
+			}
+		case *MaxEntriesOption:
+			if opt.MaxEntries < 0 {
+				invalid(opt, "negative limit %d", opt.MaxEntries)
+			}
+		case *MaxCostOption:
+			if opt.MaxCost < 0 {
+				invalid(opt, "negative limit %d", opt.MaxCost)
+			}
+		case *DefaultCallOptionsOption:
+			if err := validateOptions[T](opt.Options); err != nil {
+				errs = append(errs, err)
+			}
+		case *DefaultExpirationOption:
+			if opt.Expiration < 0 && opt.Expiration != NoExpiration {
+				invalid(opt, "negative expiration %v", opt.Expiration)
+			}
+		case *CleanupIntervalOption:
+			negative(opt, "interval", opt.Interval)
+		case *GlobalCircuitOption:
+			if opt.ErrorRate <= 0 || opt.ErrorRate > 1 {
+				invalid(opt, "error rate %v outside (0, 1]", opt.ErrorRate)
+			}
+			negative(opt, "window", opt.Window)
+			negative(opt, "cooldown", opt.Cooldown)
+		case *MaxTagsPerEntryOption:
+			if opt.MaxTags < 0 {
+				invalid(opt, "negative limit %d", opt.MaxTags)
+			}
+		case *MaxDistinctTagsOption:
+			if opt.MaxTags < 0 {
+				invalid(opt, "negative limit %d", opt.MaxTags)
+			}
+		case *MaxErrorEntriesOption:
+			if opt.MaxErrorEntries < 0 {
+				invalid(opt, "negative limit %d", opt.MaxErrorEntries)
+			}
+		case *CoalescedInvalidationOption:
+			negative(opt, "window", opt.Window)
+		case *MaxConcurrentComputationsOption:
+			if opt.MaxConcurrent < 0 {
+				invalid(opt, "negative limit %d", opt.MaxConcurrent)
+			}
+		case *CloneOption[T]:
+			if opt.Clone == nil {
+				invalid(opt, "nil callback")
+			}
+		case *CircuitBreakerOption:
+			if opt.Failures < 0 {
+				invalid(opt, "negative failure count %d", opt.Failures)
+			}
+			negative(opt, "cooldown", opt.Cooldown)
+		case *FlexibleDecodeOption[T]:
+			if opt.Decode == nil {
+				invalid(opt, "nil callback")
+			}
+		case *RecorderOption:
+			if opt.Recorder == nil {
+				invalid(opt, "nil recorder")
+			}
+		case *MaxRecursionDepthOption:
+			if opt.Depth < 0 {
+				invalid(opt, "negative depth %d", opt.Depth)
+			}
+		case *CodecOption:
+			if opt.Codec == nil {
+				invalid(opt, "nil codec")
+			}
+		case *PersistenceOption:
+			if opt.Path == "" {
+				invalid(opt, "empty path")
+			}
+		case *BroadcasterOption:
+			if opt.Broadcaster == nil {
+				invalid(opt, "nil broadcaster")
+			}
+		case *KeyHasherOption:
+			if opt.Hasher == nil {
+				invalid(opt, "nil hasher")
+			}
+		case *DistributedLockOption:
+			if opt.Locker == nil {
+				invalid(opt, "nil locker")
+			}
+			if opt.TTL <= 0 {
+				invalid(opt, "lock TTL %v must be positive", opt.TTL)
+			}
+		case *HooksOption[T], *LoggerOption, *StrictTypesOption, *MutationDetectionOption,
+			*WindowedStatsOption, *OptionValidationOption:
+
+		default:
+			// This includes typed options whose type parameter is not T, which calls ignore.
+			invalid(opt, "not an option of a Memoizer[%v]", reflect.TypeOf((*T)(nil)).Elem())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,90 @@
+package memoizer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	expiration := func(interface{}) time.Duration { return time.Minute }
+
+	assert.NoError(t, memoizer.Validate())
+	assert.NoError(t, memoizer.Validate(WithTTL(time.Minute), WithMinTTL(time.Second), WithMaxTTL(time.Hour), WithTags("a")))
+	assert.NoError(t, memoizer.Validate(WithTTL(NoExpiration)))
+	assert.NoError(t, memoizer.Validate(WithDefaultExpiration(time.Minute), WithDefaultCallOptions(WithTTL(time.Second))))
+	// A nil cost function makes values cost their size, or 1.
+	assert.NoError(t, memoizer.Validate(WithMaxCost(100, nil), WithTTLJitter(0.99)))
+
+	invalid := map[string][]Option{
+		"ttl and expiration":       {WithTTL(time.Minute), WithExpiration(expiration)},
+		"soft/hard and stale":      {WithStaleWhileRevalidate(time.Minute), WithSoftHardTTL(time.Second, time.Minute)},
+		"negative ttl":             {WithTTL(-2 * time.Second)},
+		"negative timeout":         {WithTimeout(-time.Second)},
+		"min above max":            {WithMinTTL(time.Hour), WithMaxTTL(time.Minute)},
+		"soft above hard":          {WithSoftHardTTL(time.Hour, time.Minute)},
+		"nil callback":             {WithExpiration(nil)},
+		"nil typed callback":       {WithCachePredicate[int](nil)},
+		"jitter fraction":          {WithTTLJitter(2)},
+		"whole jitter fraction":    {WithTTLJitter(1)},
+		"mismatched type":          {WithExpirationFor(func(string) time.Duration { return time.Minute })},
+		"unknown option":           {struct{}{}},
+		"nested default options":   {WithDefaultCallOptions(WithTTL(-2 * time.Second))},
+		"nil store":                {WithStore(nil)},
+		"non-positive lock ttl":    {WithDistributedLock(nil, 0)},
+		"negative max concurrency": {WithMaxConcurrentComputations(-1)},
+		"negative max errors":      {WithMaxErrorEntries(-1)},
+		"negative max tags":        {WithMaxTagsPerEntry(-1)},
+		"negative distinct tags":   {WithMaxDistinctTags(-1)},
+		"nil backend":              {WithBackend(nil)},
+		"negative coalescing":      {WithCoalescedInvalidation(-time.Second)},
+	}
+	for name, options := range invalid {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, memoizer.Validate(options...), ErrInvalidOption)
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	err := NewMemoizer[int]().Validate(WithTTL(time.Minute), WithExpiration(nil), WithTimeout(-time.Second))
+	require.Error(t, err)
+	var joined interface{ Unwrap() []error }
+	require.True(t, errors.As(err, &joined))
+	// The conflict, the nil callback and the negative timeout.
+	assert.Len(t, joined.Unwrap(), 3)
+	assert.Contains(t, err.Error(), "*memoizer.ExpirationOption: conflicts with *memoizer.TTLOption")
+}
+
+func TestWithOptionValidation(t *testing.T) {
+	memoizer := New[int](WithOptionValidation(), WithDefaultCallOptions(WithExpiration(func(interface{}) time.Duration { return time.Hour })))
+	assert.True(t, memoizer.Config().OptionValidation)
+	calls := 0
+	fn := func() (int, error) {
+		calls++
+		return 42, nil
+	}
+
+	_, err := memoizer.Memoize("key", fn, WithTTL(time.Minute), WithExpiration(nil))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	r := <-memoizer.MemoizeDoChan("key", fn, WithTimeout(-time.Second))
+	assert.ErrorIs(t, r.Err, ErrInvalidOption)
+	_, err = memoizer.MemoizeMulti("key", func() (map[string]int, error) { return nil, nil }, WithHedging(-time.Second))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	assert.Equal(t, 0, calls)
+
+	// Overriding a default is not a conflict.
+	value, err := memoizer.Memoize("key", fn, WithTTL(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+	assert.Equal(t, 1, calls)
+
+	// Without validation, the later option wins.
+	value, err = NewMemoizer[int]().Memoize("key", fn, WithTTL(time.Minute), WithExpiration(func(interface{}) time.Duration { return time.Hour }))
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
@@ -0,0 +1,41 @@
+package memoizer
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrTooManyWaiters is returned by a call with WithMaxWaiters when too many callers are
+// already waiting on the in-flight computation of its key.
+var ErrTooManyWaiters = errors.New("too many callers waiting on the computation")
+
+// waiterCounts counts the callers sharing the in-flight computation of each key, including
+// the one running it, for WithMaxWaiters.
+type waiterCounts struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// join adds a caller of key, unless max callers already wait on the computation besides the
+// one running it, in which case it reports false.
+func (w *waiterCounts) join(key string, max int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.counts[key] > max {
+		return false
+	}
+	if w.counts == nil {
+		w.counts = make(map[string]int)
+	}
+	w.counts[key]++
+	return true
+}
+
+// leave removes a caller of key once it has received the result.
+func (w *waiterCounts) leave(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.counts[key]--; w.counts[key] <= 0 {
+		delete(w.counts, key)
+	}
+}
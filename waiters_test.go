@@ -0,0 +1,60 @@
+package memoizer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxWaitersFailsFast(t *testing.T) {
+	m := NewMemoizer[int]()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fn := func() (int, error) {
+		close(started)
+		<-release
+		return 42, nil
+	}
+	limit := WithMaxWaiters(2)
+
+	var wg sync.WaitGroup
+	results := make([]error, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, results[0] = m.Memoize("key", fn, limit)
+	}()
+	<-started
+	for i := 1; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, results[i] = m.Memoize("key", fn, limit)
+		}(i)
+	}
+	assert.Eventually(t, func() bool {
+		m.waiters.mu.Lock()
+		defer m.waiters.mu.Unlock()
+		return m.waiters.counts["key"] == 3
+	}, time.Second, time.Millisecond)
+
+	_, err := m.Memoize("key", fn, limit)
+	assert.ErrorIs(t, err, ErrTooManyWaiters)
+	r := <-m.MemoizeDoChan("key", fn, limit)
+	assert.ErrorIs(t, r.Err, ErrTooManyWaiters)
+
+	close(release)
+	wg.Wait()
+	for _, err := range results {
+		require.NoError(t, err)
+	}
+	assert.Empty(t, m.waiters.counts)
+
+	// Cache hits are unaffected.
+	value, err := m.Memoize("key", fn, limit)
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
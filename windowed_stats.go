@@ -0,0 +1,114 @@
+package memoizer
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// statsBucketWidth is the granularity of windowed statistics.
+	statsBucketWidth = 10 * time.Second
+	// statsBuckets covers the longest reported window, 15 minutes.
+	statsBuckets = int(15 * time.Minute / statsBucketWidth)
+)
+
+// WindowCounts holds the number of cache hits and misses within a time window.
+type WindowCounts struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRate returns the fraction of lookups in the window that were hits, or zero if there
+// were no lookups.
+func (c WindowCounts) HitRate() float64 {
+	total := c.Hits + c.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(total)
+}
+
+// WindowedStats reports cache hits and misses over rolling windows, as returned by
+// Memoizer.WindowedStats. Counts are kept in 10-second buckets, so each window may lag
+// its nominal length by up to 10 seconds.
+type WindowedStats struct {
+	Last1m  WindowCounts
+	Last5m  WindowCounts
+	Last15m WindowCounts
+}
+
+// statsBucket holds the counts of one bucket-wide interval, identified by its index since
+// the Unix epoch.
+type statsBucket struct {
+	index int64
+	WindowCounts
+}
+
+// windowedStats is a ring buffer of statsBuckets. Buckets are rotated lazily, when they
+// are next written or read.
+type windowedStats struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets [statsBuckets]statsBucket
+}
+
+func newWindowedStats() *windowedStats {
+	return &windowedStats{now: time.Now}
+}
+
+// bucket returns the bucket for the current interval, resetting it if it still holds the
+// counts of an interval that has aged out.
+func (w *windowedStats) bucket() *statsBucket {
+	index := w.now().UnixNano() / int64(statsBucketWidth)
+	b := &w.buckets[index%int64(statsBuckets)]
+	if b.index != index {
+		*b = statsBucket{index: index}
+	}
+	return b
+}
+
+func (w *windowedStats) hit() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.bucket().Hits++
+}
+
+func (w *windowedStats) miss() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.bucket().Misses++
+}
+
+// sum adds up the counts of the buckets within the given window, ending at the current one.
+func (w *windowedStats) sum(current int64, window time.Duration) WindowCounts {
+	var counts WindowCounts
+	oldest := current - int64(window/statsBucketWidth) + 1
+	for _, b := range w.buckets {
+		if b.index >= oldest && b.index <= current {
+			counts.Hits += b.Hits
+			counts.Misses += b.Misses
+		}
+	}
+	return counts
+}
+
+func (w *windowedStats) snapshot() WindowedStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	current := w.bucket().index
+	return WindowedStats{
+		Last1m:  w.sum(current, time.Minute),
+		Last5m:  w.sum(current, 5*time.Minute),
+		Last15m: w.sum(current, 15*time.Minute),
+	}
+}
+
+// WindowedStats returns hit and miss counts over the last 1, 5 and 15 minutes. It requires
+// the Memoizer to be created with WithWindowedStats, and returns zero counts otherwise.
+func (m *Memoizer[T]) WindowedStats() WindowedStats {
+	if m.windowedStats == nil {
+		return WindowedStats{}
+	}
+	return m.windowedStats.snapshot()
+}
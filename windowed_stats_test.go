@@ -0,0 +1,51 @@
+package memoizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowedStats(t *testing.T) {
+	memoizer := NewMemoizerWithOptions[int](WithWindowedStats())
+	now := time.Unix(1_000_000, 0)
+	memoizer.windowedStats.now = func() time.Time {
+		return now
+	}
+
+	fn := func() (int, error) {
+		return 42, nil
+	}
+	for i := 0; i < 4; i++ {
+		_, err := memoizer.Memoize("key", fn)
+		require.NoError(t, err)
+	}
+	stats := memoizer.WindowedStats()
+	assert.Equal(t, WindowCounts{Hits: 3, Misses: 1}, stats.Last1m)
+	assert.Equal(t, WindowCounts{Hits: 3, Misses: 1}, stats.Last15m)
+	assert.Equal(t, 0.75, stats.Last1m.HitRate())
+
+	// After two minutes, the events have left the 1m window but not the longer ones.
+	now = now.Add(2 * time.Minute)
+	_, err := memoizer.Memoize("key", fn)
+	require.NoError(t, err)
+	stats = memoizer.WindowedStats()
+	assert.Equal(t, WindowCounts{Hits: 1}, stats.Last1m)
+	assert.Equal(t, WindowCounts{Hits: 4, Misses: 1}, stats.Last5m)
+	assert.Equal(t, WindowCounts{Hits: 4, Misses: 1}, stats.Last15m)
+
+	// After 20 minutes, everything has aged out.
+	now = now.Add(20 * time.Minute)
+	assert.Equal(t, WindowedStats{}, memoizer.WindowedStats())
+}
+
+func TestWindowedStatsDisabled(t *testing.T) {
+	memoizer := NewMemoizer[int]()
+	_, err := memoizer.Memoize("key", func() (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, WindowedStats{}, memoizer.WindowedStats())
+}
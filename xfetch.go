@@ -0,0 +1,32 @@
+package memoizer
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// computed records that the value cached for key took d to compute, for early expiration.
+func (a *accessTracker) computed(key string, d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if stat, ok := a.stats[key]; ok {
+		stat.computeTime = d
+		a.stats[key] = stat
+	}
+}
+
+// expiresEarly decides whether a hit on key triggers its recomputation ahead of its expiration,
+// following the XFetch algorithm of "Optimal Probabilistic Cache Stampede Prevention" (Vattani
+// et al.): the closer the entry is to expiring, and the longer it takes to compute, the likelier
+// a hit is to trigger it. beta scales the likelihood.
+func (a *accessTracker) expiresEarly(key string, beta float64) bool {
+	stat := a.get(key)
+	if stat.dueAt.IsZero() || stat.computeTime <= 0 {
+		return false
+	}
+	// 1-rand.Float64() is in (0, 1], so the logarithm is finite and non-positive. The gap is
+	// compared as a float, since it may exceed the range of a time.Duration for large betas.
+	gap := -float64(stat.computeTime) * beta * math.Log(1-rand.Float64())
+	return gap >= float64(time.Until(stat.dueAt))
+}
@@ -0,0 +1,72 @@
+package memoizer
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEarlyExpirationRecomputesBeforeExpiration(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		time.Sleep(5 * time.Millisecond)
+		return int(calls.Add(1)), nil
+	}
+	options := []Option{
+		WithExpiration(func(interface{}) time.Duration { return time.Hour }),
+		// A huge beta makes every hit recompute, however far the expiration.
+		WithEarlyExpiration(1e12),
+	}
+
+	value, err := m.Memoize("key", fn, options...)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	value, err = m.Memoize("key", fn, options...)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value, "the hit should be served the cached value")
+	assert.Eventually(t, func() bool {
+		value, _ := m.Peek("key")
+		return value == 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestEarlyExpirationLeavesDistantExpirationsAlone(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		time.Sleep(time.Millisecond)
+		return int(calls.Add(1)), nil
+	}
+	options := []Option{
+		WithExpiration(func(interface{}) time.Duration { return time.Hour }),
+		WithEarlyExpiration(1),
+	}
+
+	for i := 0; i < 100; i++ {
+		_, err := m.Memoize("key", fn, options...)
+		require.NoError(t, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestEarlyExpirationIgnoresEntriesThatNeverExpire(t *testing.T) {
+	m := NewMemoizer[int]()
+	var calls atomic.Int32
+	fn := func() (int, error) {
+		time.Sleep(time.Millisecond)
+		return int(calls.Add(1)), nil
+	}
+
+	for i := 0; i < 10; i++ {
+		_, err := m.Memoize("key", fn, WithEarlyExpiration(1e12))
+		require.NoError(t, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.EqualValues(t, 1, calls.Load())
+}